@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// titleRe matches the puzzle heading of a day's write-up,
+// e.g. "# Day 6: Trash Compactor" or "# AoC25: Day 1 Secret Entrance".
+var titleRe = regexp.MustCompile(`Day\s+(\d+):?\s+(.+)$`)
+
+// Day describes a solved day found in the repository.
+type Day struct {
+	Number     int
+	Dir        string    // directory name, e.g. "day01"
+	Title      string    // puzzle name from the day's write-up
+	HasExample bool      // example_test.go defines TestExample
+	Answers    [2]string // golden answers for part 1 and 2, empty if not recorded
+	Solved     [2]bool   // the solver's answer matches the golden answer, set by verifyAnswers
+}
+
+// Stars returns the number of parts whose golden answer the solver matches.
+func (d Day) Stars() int {
+	stars := 0
+	for _, solved := range d.Solved {
+		if solved {
+			stars++
+		}
+	}
+	return stars
+}
+
+// URL returns the puzzle page for the day.
+func (d Day) URL() string {
+	return fmt.Sprintf("https://adventofcode.com/2025/day/%d", d.Number)
+}
+
+// loadDays discovers every dayNN directory under root, in day order.
+func loadDays(root string) ([]Day, error) {
+	dirs, err := filepath.Glob(filepath.Join(root, "day[0-9][0-9]"))
+	if err != nil {
+		return nil, err
+	}
+
+	days := make([]Day, 0, len(dirs))
+	for _, dir := range dirs {
+		day, err := loadDay(dir)
+		if err != nil {
+			return nil, err
+		}
+		days = append(days, day)
+	}
+
+	return days, nil
+}
+
+// verifyAnswers runs every day's solver on its real input and records which
+// parts match their golden answers. A day that fails to run earns no stars.
+func verifyAnswers(root string, days []Day) {
+	for i := range days {
+		days[i].Solved = solveDay(root, days[i]).Correct
+	}
+}
+
+// loadDay reads the write-up, example test and golden answers of a single day.
+func loadDay(dir string) (Day, error) {
+	name := filepath.Base(dir)
+	num, err := strconv.Atoi(strings.TrimPrefix(name, "day"))
+	if err != nil {
+		return Day{}, fmt.Errorf("%s: %w", name, err)
+	}
+	day := Day{Number: num, Dir: name}
+
+	// Puzzle title from the first line of the write-up
+	md, err := os.ReadFile(filepath.Join(dir, name+".md"))
+	if err != nil {
+		return Day{}, err
+	}
+	firstLine, _, _ := bytes.Cut(md, []byte("\n"))
+	if m := titleRe.FindSubmatch(bytes.TrimSpace(firstLine)); m != nil {
+		day.Title = string(m[2])
+	}
+
+	// Example test
+	test, err := os.ReadFile(filepath.Join(dir, "example_test.go"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return Day{}, err
+	}
+	day.HasExample = bytes.Contains(test, []byte("func TestExample("))
+
+	// Golden answers
+	day.Answers, err = readAnswers(filepath.Join(dir, "answers.txt"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return Day{}, err
+	}
+
+	return day, nil
+}
+
+// readAnswers parses a golden answers file with "part1: N" and "part2: N" lines.
+func readAnswers(path string) ([2]string, error) {
+	var answers [2]string
+
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return answers, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+
+		switch strings.TrimSpace(key) {
+		case "part1":
+			answers[0] = strings.TrimSpace(value)
+		case "part2":
+			answers[1] = strings.TrimSpace(value)
+		}
+	}
+
+	return answers, scanner.Err()
+}
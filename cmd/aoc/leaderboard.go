@@ -0,0 +1,283 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Leaderboard is the JSON export of a private leaderboard.
+type Leaderboard struct {
+	Event   string             `json:"event"`
+	NumDays int                `json:"num_days"`
+	Members map[string]*Member `json:"members"`
+}
+
+// Member is a single leaderboard member. CompletionDayLevel maps
+// day -> part -> star, using the string keys of the export.
+type Member struct {
+	ID                 int                                  `json:"id"`
+	Name               string                               `json:"name"`
+	Stars              int                                  `json:"stars"`
+	LocalScore         int                                  `json:"local_score"`
+	CompletionDayLevel map[string]map[string]StarCompletion `json:"completion_day_level"`
+}
+
+// StarCompletion records when a star was earned.
+type StarCompletion struct {
+	GetStarTS int64 `json:"get_star_ts"`
+}
+
+// DayTimes holds how long a member took to earn each star of a day,
+// measured from when the puzzle unlocked. Zero means the star is missing.
+type DayTimes struct {
+	Part1 time.Duration
+	Part2 time.Duration
+}
+
+// Delta returns the time between the two stars of a day.
+func (d DayTimes) Delta() (time.Duration, bool) {
+	if d.Part1 == 0 || d.Part2 == 0 {
+		return 0, false
+	}
+	return d.Part2 - d.Part1, true
+}
+
+// MemberStats is the computed statistics for one member.
+type MemberStats struct {
+	Name   string
+	Score  int
+	Stars  int
+	Times  []DayTimes // indexed by day-1
+	Ranks  []int      // rank after each day, by cumulative local score
+	Scores []int      // cumulative local score after each day
+}
+
+// LeaderboardStats is the computed statistics for a whole leaderboard,
+// with members ordered by final rank.
+type LeaderboardStats struct {
+	Event   string
+	NumDays int
+	Members []*MemberStats
+}
+
+// runLeaderboard reads a leaderboard export and renders its statistics.
+func runLeaderboard(args []string) error {
+	var inputFile, url, session, format, output string
+
+	fs := flag.NewFlagSet("leaderboard", flag.ExitOnError)
+	fs.StringVar(&inputFile, "i", "", "leaderboard JSON export")
+	fs.StringVar(&url, "url", "", "fetch the leaderboard JSON from this URL instead of a file")
+	fs.StringVar(&session, "session", "", "session cookie sent with -url")
+	fs.StringVar(&format, "format", "table", "output format: table, csv or html")
+	fs.StringVar(&output, "o", "", "output file (default stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var lb *Leaderboard
+	var err error
+	switch {
+	case url != "":
+		lb, err = fetchLeaderboard(context.Background(), url, session)
+	case inputFile != "":
+		lb, err = readLeaderboardFile(inputFile)
+	default:
+		return errors.New("leaderboard: one of -i or -url is required")
+	}
+	if err != nil {
+		return err
+	}
+
+	stats, err := computeLeaderboardStats(lb)
+	if err != nil {
+		return err
+	}
+
+	w := io.Writer(os.Stdout)
+	if output != "" {
+		f, err := os.Create(filepath.Clean(output))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch format {
+	case "table":
+		return writeLeaderboardTable(w, stats)
+	case "csv":
+		return writeLeaderboardCSV(w, stats)
+	case "html":
+		return writeLeaderboardHTML(w, stats)
+	default:
+		return fmt.Errorf("leaderboard: unknown format %q", format)
+	}
+}
+
+// readLeaderboardFile decodes a leaderboard export from disk.
+func readLeaderboardFile(path string) (*Leaderboard, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return decodeLeaderboard(f)
+}
+
+// fetchLeaderboard downloads a leaderboard export, e.g. from a local stand-in server.
+func fetchLeaderboard(ctx context.Context, url, session string) (*Leaderboard, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	if session != "" {
+		req.AddCookie(&http.Cookie{Name: "session", Value: session})
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("leaderboard: fetch %s: %s", url, resp.Status)
+	}
+
+	return decodeLeaderboard(resp.Body)
+}
+
+func decodeLeaderboard(r io.Reader) (*Leaderboard, error) {
+	var lb Leaderboard
+	if err := json.NewDecoder(r).Decode(&lb); err != nil {
+		return nil, fmt.Errorf("leaderboard: %w", err)
+	}
+	return &lb, nil
+}
+
+// computeLeaderboardStats works out star times, part deltas and the rank
+// history. Local score follows the site's rules: for each star, the first
+// member to earn it gets one point per member, the next one less, and so on.
+func computeLeaderboardStats(lb *Leaderboard) (*LeaderboardStats, error) {
+	year, err := strconv.Atoi(lb.Event)
+	if err != nil {
+		return nil, fmt.Errorf("leaderboard: invalid event %q", lb.Event)
+	}
+
+	members := make([]*Member, 0, len(lb.Members))
+	for _, m := range lb.Members {
+		members = append(members, m)
+	}
+	slices.SortFunc(members, func(a, b *Member) int { return cmp.Compare(a.ID, b.ID) })
+
+	numDays := lb.NumDays
+	for _, m := range members {
+		for key := range m.CompletionDayLevel {
+			if day, err := strconv.Atoi(key); err == nil {
+				numDays = max(numDays, day)
+			}
+		}
+	}
+
+	stats := &LeaderboardStats{Event: lb.Event, NumDays: numDays}
+	for _, m := range members {
+		name := m.Name
+		if name == "" {
+			name = fmt.Sprintf("(anonymous user #%d)", m.ID)
+		}
+		stats.Members = append(stats.Members, &MemberStats{
+			Name:   name,
+			Stars:  m.Stars,
+			Times:  make([]DayTimes, numDays),
+			Ranks:  make([]int, numDays),
+			Scores: make([]int, numDays),
+		})
+	}
+
+	for day := 1; day <= numDays; day++ {
+		unlock := time.Date(year, time.December, day, 5, 0, 0, 0, time.UTC)
+
+		for part := 1; part <= 2; part++ {
+			type finisher struct {
+				member int
+				ts     int64
+			}
+
+			var finishers []finisher
+			for i, m := range members {
+				star, ok := m.CompletionDayLevel[strconv.Itoa(day)][strconv.Itoa(part)]
+				if !ok {
+					continue
+				}
+				finishers = append(finishers, finisher{i, star.GetStarTS})
+
+				took := time.Unix(star.GetStarTS, 0).Sub(unlock)
+				if part == 1 {
+					stats.Members[i].Times[day-1].Part1 = took
+				} else {
+					stats.Members[i].Times[day-1].Part2 = took
+				}
+			}
+
+			slices.SortStableFunc(finishers, func(a, b finisher) int { return cmp.Compare(a.ts, b.ts) })
+			for place, f := range finishers {
+				stats.Members[f.member].Score += len(members) - place
+			}
+		}
+
+		for _, ms := range stats.Members {
+			ms.Scores[day-1] = ms.Score
+		}
+		for _, ms := range stats.Members {
+			ms.Ranks[day-1] = rankOf(stats.Members, ms.Score)
+		}
+	}
+
+	slices.SortStableFunc(stats.Members, func(a, b *MemberStats) int { return cmp.Compare(b.Score, a.Score) })
+	return stats, nil
+}
+
+// rankOf returns the competition rank of score: one more than the number
+// of members with a strictly higher score, so ties share a rank.
+func rankOf(members []*MemberStats, score int) int {
+	rank := 1
+	for _, m := range members {
+		if m.Score > score {
+			rank++
+		}
+	}
+	return rank
+}
+
+// formatDuration renders a star time as h:mm:ss, or "-" when missing.
+func formatDuration(d time.Duration) string {
+	if d == 0 {
+		return "-"
+	}
+	return hms(d)
+}
+
+// hms renders a duration as h:mm:ss.
+func hms(d time.Duration) string {
+	d = d.Round(time.Second)
+	h := int(d / time.Hour)
+	m := int(d % time.Hour / time.Minute)
+	s := int(d % time.Minute / time.Second)
+	return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+}
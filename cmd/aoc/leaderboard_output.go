@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// writeLeaderboardTable renders the standings with rank history,
+// followed by each member's star times per day.
+func writeLeaderboardTable(w io.Writer, stats *LeaderboardStats) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Leaderboard %s\n\n", stats.Event)
+	fmt.Fprintln(tw, "RANK\tNAME\tSCORE\tSTARS\tRANK HISTORY")
+	for _, m := range stats.Members {
+		fmt.Fprintf(tw, "%d\t%s\t%d\t%d\t%s\n", m.Rank(), m.Name, m.Score, m.Stars, m.RankHistory())
+	}
+
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "DAY\tNAME\tPART 1\tPART 2\tDELTA")
+	for day := range stats.NumDays {
+		for _, m := range stats.Members {
+			t := m.Times[day]
+			if t.Part1 == 0 && t.Part2 == 0 {
+				continue
+			}
+			fmt.Fprintf(tw, "%02d\t%s\t%s\t%s\t%s\n",
+				day+1, m.Name, formatDuration(t.Part1), formatDuration(t.Part2), formatDelta(t))
+		}
+	}
+
+	return tw.Flush()
+}
+
+// writeLeaderboardCSV writes one row per member and day, with times in seconds.
+func writeLeaderboardCSV(w io.Writer, stats *LeaderboardStats) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"name", "day", "part1_seconds", "part2_seconds", "delta_seconds", "score", "rank"})
+
+	for _, m := range stats.Members {
+		for day, t := range m.Times {
+			delta, _ := t.Delta()
+			_ = cw.Write([]string{
+				m.Name,
+				strconv.Itoa(day + 1),
+				csvSeconds(t.Part1),
+				csvSeconds(t.Part2),
+				csvSeconds(delta),
+				strconv.Itoa(m.Scores[day]),
+				strconv.Itoa(m.Ranks[day]),
+			})
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// leaderboardHTML is a static page with no external assets.
+var leaderboardHTML = template.Must(template.New("leaderboard").Funcs(template.FuncMap{
+	"duration": formatDuration,
+	"delta":    formatDelta,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Leaderboard {{.Event}}</title>
+<style>
+body { font-family: monospace; background: #0f0f23; color: #cccccc; margin: 2em; }
+h1, h2 { color: #00cc00; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { padding: 0.2em 0.8em; text-align: right; border-bottom: 1px solid #333340; }
+th { color: #ffff66; }
+td.name { text-align: left; }
+</style>
+</head>
+<body>
+<h1>Leaderboard {{.Event}}</h1>
+
+<h2>Standings</h2>
+<table>
+<tr><th>Rank</th><th>Name</th><th>Score</th><th>Stars</th>{{range $d := .Days}}<th>Day {{$d}}</th>{{end}}</tr>
+{{- range .Stats.Members}}
+<tr><td>{{.Rank}}</td><td class="name">{{.Name}}</td><td>{{.Score}}</td><td>{{.Stars}}</td>{{range .Ranks}}<td>{{.}}</td>{{end}}</tr>
+{{- end}}
+</table>
+
+<h2>Star times</h2>
+{{- range $i, $d := .Days}}
+<h3>Day {{$d}}</h3>
+<table>
+<tr><th>Name</th><th>Part 1</th><th>Part 2</th><th>Delta</th></tr>
+{{- range $.Stats.Members}}
+{{- $name := .Name}}
+{{- with index .Times $i}}{{if or .Part1 .Part2}}
+<tr><td class="name">{{$name}}</td><td>{{duration .Part1}}</td><td>{{duration .Part2}}</td><td>{{delta .}}</td></tr>
+{{- end}}{{end}}
+{{- end}}
+</table>
+{{- end}}
+</body>
+</html>
+`))
+
+// writeLeaderboardHTML renders the standings and star times as a static page.
+func writeLeaderboardHTML(w io.Writer, stats *LeaderboardStats) error {
+	days := make([]int, stats.NumDays)
+	for i := range days {
+		days[i] = i + 1
+	}
+
+	return leaderboardHTML.Execute(w, struct {
+		Event string
+		Days  []int
+		Stats *LeaderboardStats
+	}{stats.Event, days, stats})
+}
+
+// Rank returns the member's final rank.
+func (m *MemberStats) Rank() int {
+	if len(m.Ranks) == 0 {
+		return 0
+	}
+	return m.Ranks[len(m.Ranks)-1]
+}
+
+// RankHistory renders the rank after each day, e.g. "3 2 2 1".
+func (m *MemberStats) RankHistory() string {
+	ranks := make([]string, len(m.Ranks))
+	for i, r := range m.Ranks {
+		ranks[i] = strconv.Itoa(r)
+	}
+	return strings.Join(ranks, " ")
+}
+
+func formatDelta(t DayTimes) string {
+	delta, ok := t.Delta()
+	if !ok {
+		return "-"
+	}
+	return "+" + hms(delta)
+}
+
+func csvSeconds(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return strconv.FormatInt(int64(d/time.Second), 10)
+}
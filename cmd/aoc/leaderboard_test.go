@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// exampleLeaderboard has three members over two days. Day 1 unlocks at
+// 1764565200 (2025-12-01 05:00 UTC) and day 2 at 1764651600.
+const exampleLeaderboard = `{
+  "event": "2025",
+  "num_days": 2,
+  "members": {
+    "1": {"id": 1, "name": "alice", "stars": 4, "completion_day_level": {
+      "1": {"1": {"get_star_ts": 1764565800}, "2": {"get_star_ts": 1764566400}},
+      "2": {"1": {"get_star_ts": 1764655200}, "2": {"get_star_ts": 1764658800}}
+    }},
+    "2": {"id": 2, "name": "bob", "stars": 3, "completion_day_level": {
+      "1": {"1": {"get_star_ts": 1764565500}, "2": {"get_star_ts": 1764567000}},
+      "2": {"1": {"get_star_ts": 1764652200}}
+    }},
+    "3": {"id": 3, "name": "", "stars": 1, "completion_day_level": {
+      "2": {"1": {"get_star_ts": 1764660000}}
+    }}
+  }
+}`
+
+func TestLeaderboardStats(t *testing.T) {
+	lb, err := decodeLeaderboard(strings.NewReader(exampleLeaderboard))
+	require.NoError(t, err)
+
+	stats, err := computeLeaderboardStats(lb)
+	require.NoError(t, err)
+	require.Len(t, stats.Members, 3)
+
+	// Day 1: bob 3+2, alice 2+3. Day 2: bob 3, alice 2+3, anon 1.
+	alice, bob, anon := stats.Members[0], stats.Members[1], stats.Members[2]
+	require.Equal(t, "alice", alice.Name)
+	require.Equal(t, 10, alice.Score)
+	require.Equal(t, []int{1, 1}, alice.Ranks)
+	require.Equal(t, 8, bob.Score)
+	require.Equal(t, []int{1, 2}, bob.Ranks)
+	require.Equal(t, "(anonymous user #3)", anon.Name)
+	require.Equal(t, []int{3, 3}, anon.Ranks)
+
+	require.Equal(t, 10*time.Minute, alice.Times[0].Part1)
+	delta, ok := alice.Times[1].Delta()
+	require.True(t, ok)
+	require.Equal(t, time.Hour, delta)
+
+	_, ok = bob.Times[1].Delta()
+	require.False(t, ok)
+}
+
+func TestLeaderboardFetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("session")
+		if err != nil || cookie.Value != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(exampleLeaderboard))
+	}))
+	defer srv.Close()
+
+	lb, err := fetchLeaderboard(t.Context(), srv.URL, "secret")
+	require.NoError(t, err)
+	require.Len(t, lb.Members, 3)
+
+	_, err = fetchLeaderboard(t.Context(), srv.URL, "")
+	require.Error(t, err)
+}
+
+func TestLeaderboardOutput(t *testing.T) {
+	lb, err := decodeLeaderboard(strings.NewReader(exampleLeaderboard))
+	require.NoError(t, err)
+	stats, err := computeLeaderboardStats(lb)
+	require.NoError(t, err)
+
+	var table, csv, html bytes.Buffer
+	require.NoError(t, writeLeaderboardTable(&table, stats))
+	require.NoError(t, writeLeaderboardCSV(&csv, stats))
+	require.NoError(t, writeLeaderboardHTML(&html, stats))
+
+	require.Contains(t, table.String(), "0:10:00")
+	require.Contains(t, csv.String(), "alice,2,3600,7200,3600,10,1")
+	require.Contains(t, html.String(), "(anonymous user #3)")
+	require.NotContains(t, html.String(), "http")
+}
@@ -0,0 +1,53 @@
+// Command aoc provides tooling that works across all the days in the repo.
+package main
+
+//go:generate go run . readme -root ../.. -o ../../README.md
+
+import (
+	"fmt"
+	"log"
+	"os"
+)
+
+// command is a subcommand of aoc that receives its own arguments.
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands = []command{
+	{"progress", "report stars per day by checking each solver against its golden answers", runProgress},
+	{"leaderboard", "analyze a private leaderboard JSON export", runLeaderboard},
+	{"report", "solve every day and write a self-contained HTML report", runReport},
+	{"readme", "regenerate the README day table and usage section", runReadme},
+}
+
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(2)
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == os.Args[1] {
+			if err := cmd.run(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+	}
+
+	printUsage()
+	os.Exit(2)
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "usage: aoc <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", cmd.name, cmd.usage)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"text/tabwriter"
+)
+
+// runProgress prints a table of stars per day.
+// A part earns a star when the day's solver reproduces the golden answer
+// recorded in answers.txt.
+func runProgress(args []string) error {
+	var root string
+	var verify bool
+
+	fs := flag.NewFlagSet("progress", flag.ExitOnError)
+	fs.StringVar(&root, "root", ".", "repository root")
+	fs.BoolVar(&verify, "verify", false, "also run each day's example test")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	days, err := loadDays(root)
+	if err != nil {
+		return err
+	}
+	verifyAnswers(root, days)
+
+	examples := make([]string, len(days))
+	for i, day := range days {
+		examples[i] = exampleStatus(root, day, verify)
+	}
+
+	return writeProgress(os.Stdout, days, examples)
+}
+
+// exampleStatus describes the example test of a day, running it if verify is set.
+func exampleStatus(root string, day Day, verify bool) string {
+	if !day.HasExample {
+		return "missing"
+	}
+	if !verify {
+		return "yes"
+	}
+
+	cmd := exec.Command("go", "test", "-run", "^TestExample", "./"+day.Dir)
+	cmd.Dir = root
+	if err := cmd.Run(); err != nil {
+		return "FAIL"
+	}
+	return "pass"
+}
+
+// writeProgress renders the progress table with a total star count.
+func writeProgress(w io.Writer, days []Day, examples []string) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "DAY\tPUZZLE\tEXAMPLE\tPART 1\tPART 2\tSTARS")
+
+	total := 0
+	for i, day := range days {
+		total += day.Stars()
+		fmt.Fprintf(tw, "%02d\t%s\t%s\t%s\t%s\t%s\n",
+			day.Number,
+			day.Title,
+			examples[i],
+			orDash(day.Answers[0]),
+			orDash(day.Answers[1]),
+			strings.Repeat("*", day.Stars()),
+		)
+	}
+
+	fmt.Fprintf(tw, "\t\t\t\t\t%d/%d\n", total, 2*len(days))
+	return tw.Flush()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+//go:embed readme.md.tmpl
+var readmeTemplate string
+
+// runReadme regenerates the README from the discovered days.
+func runReadme(args []string) error {
+	var root, output string
+
+	fs := flag.NewFlagSet("readme", flag.ExitOnError)
+	fs.StringVar(&root, "root", ".", "repository root")
+	fs.StringVar(&output, "o", "README.md", "output file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	days, err := loadDays(root)
+	if err != nil {
+		return err
+	}
+	verifyAnswers(root, days)
+
+	var buf bytes.Buffer
+	if err := renderReadme(&buf, days); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Clean(output), buf.Bytes(), 0o600)
+}
+
+// renderReadme executes the README template with the day table,
+// usage section and reference links.
+func renderReadme(buf *bytes.Buffer, days []Day) error {
+	tmpl, err := template.New("readme").Parse(readmeTemplate)
+	if err != nil {
+		return err
+	}
+
+	return tmpl.Execute(buf, struct {
+		Table string
+		Usage string
+		Links string
+	}{
+		Table: readmeTable(days),
+		Usage: readmeUsage(days),
+		Links: readmeLinks(days),
+	})
+}
+
+// readmeTable renders the markdown table of days, padded to line up.
+func readmeTable(days []Day) string {
+	width := len("Puzzle")
+	for _, day := range days {
+		width = max(width, len(day.Title)+2)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "| Day | Solution | %-*s | Stars |\n", width, "Puzzle")
+	fmt.Fprintf(&sb, "|-----|----------|-%s-|-------|\n", strings.Repeat("-", width))
+	for _, day := range days {
+		fmt.Fprintf(&sb, "| %02d  | %-8s | %-*s | %-5s |\n",
+			day.Number,
+			"["+day.Dir+"/]",
+			width,
+			"["+day.Title+"]",
+			strings.Repeat("*", day.Stars()),
+		)
+	}
+	return sb.String()
+}
+
+// readmeUsage renders the just recipes, using the first day as the example.
+func readmeUsage(days []Day) string {
+	if len(days) == 0 {
+		return "just test         # Run all tests\n"
+	}
+	first := days[0]
+
+	lines := [][2]string{
+		{"just run " + first.Dir, fmt.Sprintf("Run Day %d with real input", first.Number)},
+		{"just test " + first.Dir, fmt.Sprintf("Run Day %d tests (example input)", first.Number)},
+		{},
+		{"just test", "Run all tests"},
+		{"just progress", "Show stars per day"},
+		{"just readme", "Regenerate this README"},
+	}
+
+	var sb strings.Builder
+	for _, line := range lines {
+		if line[0] == "" {
+			sb.WriteString("\n")
+			continue
+		}
+		fmt.Fprintf(&sb, "%-17s # %s\n", line[0], line[1])
+	}
+	return sb.String()
+}
+
+// readmeLinks renders the reference-style links used by the table.
+func readmeLinks(days []Day) string {
+	var sb strings.Builder
+	for _, day := range days {
+		fmt.Fprintf(&sb, "[%s/]: ./%s/\n", day.Dir, day.Dir)
+	}
+	for _, day := range days {
+		fmt.Fprintf(&sb, "[%s]: %s\n", day.Title, day.URL())
+	}
+	return sb.String()
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestReadmeUpToDate fails when README.md differs from what go generate would write.
+// Stars come from running every solver, so it is skipped in short mode.
+func TestReadmeUpToDate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs every day's solver")
+	}
+	days, err := loadDays("../..")
+	require.NoError(t, err)
+	verifyAnswers("../..", days)
+
+	var buf bytes.Buffer
+	require.NoError(t, renderReadme(&buf, days))
+
+	readme, err := os.ReadFile("../../README.md")
+	require.NoError(t, err)
+	require.Equal(t, buf.String(), string(readme), "README.md is stale, run: just readme")
+}
+
+func TestStarsNeedMatchingAnswers(t *testing.T) {
+	day := Day{Number: 1, Dir: "day01", Answers: [2]string{"3", "6"}}
+	require.Zero(t, day.Stars(), "recorded answers alone earn no stars")
+
+	day.Solved = [2]bool{true, false}
+	require.Equal(t, 1, day.Stars())
+
+	var buf bytes.Buffer
+	require.NoError(t, writeProgress(&buf, []Day{day}, []string{"yes"}))
+	require.Contains(t, buf.String(), "1/2")
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/lcox74/aoc25/internal/report"
+)
+
+// dayReport is a day's run alongside what the report needs to present it.
+type dayReport struct {
+	Day     Day
+	Run     report.Run
+	Err     string
+	Correct [2]bool // answer matches the golden answer
+}
+
+// SVG marks the day's visualization as safe to embed. It is produced by
+// the solvers in this repository, which escape any puzzle input text they
+// write into it.
+func (d dayReport) SVG() template.HTML {
+	return template.HTML(d.Run.SVG) //nolint:gosec // solvers escape input text in their SVGs
+}
+
+// runReport solves every day with its real input and writes a single
+// self-contained HTML report of answers, timings and visualizations.
+func runReport(args []string) error {
+	var root, output string
+
+	fs := flag.NewFlagSet("report", flag.ExitOnError)
+	fs.StringVar(&root, "root", ".", "repository root")
+	fs.StringVar(&output, "o", "report.html", "output file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	days, err := loadDays(root)
+	if err != nil {
+		return err
+	}
+
+	reports := make([]dayReport, len(days))
+	for i, day := range days {
+		reports[i] = solveDay(root, day)
+		fmt.Fprintf(os.Stderr, "day %02d: %s\n", day.Number, reports[i].status())
+	}
+
+	f, err := os.Create(filepath.Clean(output))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return writeReport(f, reports)
+}
+
+// solveDay runs a day's solver with -report and decodes its run record.
+func solveDay(root string, day Day) dayReport {
+	dr := dayReport{Day: day}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("go", "run", "./"+day.Dir, "-i", filepath.Join(day.Dir, "input.txt"), "-report")
+	cmd.Dir = root
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		dr.Err = fmt.Sprintf("%v: %s", err, bytes.TrimSpace(stderr.Bytes()))
+		return dr
+	}
+
+	if err := json.Unmarshal(stdout.Bytes(), &dr.Run); err != nil {
+		dr.Err = err.Error()
+		return dr
+	}
+
+	for part := range 2 {
+		dr.Correct[part] = day.Answers[part] != "" && part < len(dr.Run.Answers) && dr.Run.Answers[part] == day.Answers[part]
+	}
+	return dr
+}
+
+func (d dayReport) status() string {
+	if d.Err != "" {
+		return "error: " + d.Err
+	}
+	return fmt.Sprintf("%v in %v", d.Run.Answers, d.Run.SolveTime)
+}
+
+// reportHTML is the report page. It embeds all styling and SVGs inline
+// so the file can be shared on its own.
+var reportHTML = template.Must(template.New("report").Funcs(template.FuncMap{
+	"answer": func(answers []string, part int) string {
+		if part < len(answers) {
+			return answers[part]
+		}
+		return "-"
+	},
+	"ms": func(d time.Duration) string {
+		return fmt.Sprintf("%.3f ms", float64(d)/float64(time.Millisecond))
+	},
+	"kib": func(b uint64) string {
+		return fmt.Sprintf("%.1f KiB", float64(b)/1024)
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Advent of Code 2025 report</title>
+<style>
+body { font-family: monospace; background: #0f0f23; color: #cccccc; margin: 2em; }
+h1, h2 { color: #00cc00; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { padding: 0.2em 0.8em; text-align: right; border-bottom: 1px solid #333340; }
+th { color: #ffff66; }
+td.name { text-align: left; }
+.ok { color: #00cc00; }
+.bad { color: #ff6666; }
+svg { max-width: 100%; height: auto; border: 1px solid #333340; }
+</style>
+</head>
+<body>
+<h1>Advent of Code 2025 report</h1>
+<p>Generated {{.Generated.Format "2006-01-02 15:04:05 MST"}}. Allocations cover parsing and solving. Days that parse while they solve have no parse time, and their solve time covers both.</p>
+
+<table>
+<tr><th>Day</th><th>Puzzle</th><th>Part 1</th><th>Part 2</th><th>Read</th><th>Parse</th><th>Solve</th><th>Allocs</th><th>Allocated</th><th>Peak heap</th></tr>
+{{- range .Days}}
+<tr>
+<td>{{printf "%02d" .Day.Number}}</td>
+<td class="name">{{.Day.Title}}</td>
+{{- if .Err}}
+<td class="bad name" colspan="8">{{.Err}}</td>
+{{- else}}
+<td class="{{if index .Correct 0}}ok{{else}}bad{{end}}">{{answer .Run.Answers 0}}</td>
+<td class="{{if index .Correct 1}}ok{{else}}bad{{end}}">{{answer .Run.Answers 1}}</td>
+<td>{{ms .Run.ReadTime}}</td>
+<td>{{if .Run.ParseTime}}{{ms .Run.ParseTime}}{{else}}-{{end}}</td>
+<td>{{ms .Run.SolveTime}}</td>
+<td>{{.Run.Allocs}}</td>
+<td>{{kib .Run.Bytes}}</td>
+<td>{{kib .Run.PeakBytes}}</td>
+{{- end}}
+</tr>
+{{- end}}
+</table>
+{{range .Days}}{{if .Run.SVG}}
+<h2>Day {{printf "%02d" .Day.Number}}: {{.Day.Title}}</h2>
+{{.SVG}}
+{{end}}{{end}}
+</body>
+</html>
+`))
+
+// writeReport renders the report page for the solved days.
+func writeReport(w io.Writer, reports []dayReport) error {
+	return reportHTML.Execute(w, struct {
+		Generated time.Time
+		Days      []dayReport
+	}{time.Now(), reports})
+}
@@ -0,0 +1,44 @@
+package main_test
+
+import (
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	main "github.com/lcox74/aoc25/day01"
+	"github.com/lcox74/aoc25/internal/report"
+)
+
+// budget for parsing and solving generatedInput, about 25% over measured.
+var budget = report.Budget{Allocs: 13, Bytes: 5_200}
+
+// generatedInput returns 10,000 random rotations of up to 1,000 clicks.
+func generatedInput() string {
+	rng := rand.New(rand.NewPCG(29, 1))
+
+	var sb strings.Builder
+	for range 10_000 {
+		dir := "R"
+		if rng.IntN(2) == 0 {
+			dir = "L"
+		}
+		fmt.Fprintf(&sb, "%s%d\n", dir, rng.IntN(1000)+1)
+	}
+	return sb.String()
+}
+
+func TestAllocBudget(t *testing.T) {
+	budget.Check(t, generatedInput(), func(r io.Reader) {
+		main.NewDial().Parse(r)
+	})
+}
+
+func BenchmarkParse(b *testing.B) {
+	input := generatedInput()
+	b.ReportAllocs()
+	for b.Loop() {
+		main.NewDial().Parse(strings.NewReader(input))
+	}
+}
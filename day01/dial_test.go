@@ -0,0 +1,310 @@
+package main_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	main "github.com/lcox74/aoc25/day01"
+	"github.com/stretchr/testify/require"
+)
+
+// clickByClick rotates a dial one click at a time, counting how often each
+// marker is passed and landed on, as a reference for the closed-form counts.
+func clickByClick(size, start int, markers []int, rotations []int) (value int, landed, passed []int) {
+	value = start
+	landed = make([]int, len(markers))
+	passed = make([]int, len(markers))
+
+	for _, n := range rotations {
+		step := 1
+		if n < 0 {
+			step, n = -1, -n
+		}
+		for range n {
+			value = ((value+step)%size + size) % size
+			for i, m := range markers {
+				if value == m {
+					passed[i]++
+				}
+			}
+		}
+		for i, m := range markers {
+			if value == m {
+				landed[i]++
+			}
+		}
+	}
+	return value, landed, passed
+}
+
+func TestDialOptions(t *testing.T) {
+	rng := rand.New(rand.NewPCG(31, 1))
+
+	for range 50 {
+		size := rng.IntN(20) + 1
+		start := rng.IntN(size)
+		markers := []int{rng.IntN(size), rng.IntN(size), 0}
+
+		var input strings.Builder
+		rotations := make([]int, 30)
+		for i := range rotations {
+			rotations[i] = rng.IntN(4*size) - 2*size
+			if rotations[i] < 0 {
+				fmt.Fprintf(&input, "L%d\n", -rotations[i])
+			} else {
+				fmt.Fprintf(&input, "R%d\n", rotations[i])
+			}
+		}
+
+		dial := main.NewDial(main.WithSize(size), main.WithStart(start), main.WithMarkers(markers...))
+		dial.Parse(strings.NewReader(input.String()))
+
+		value, landed, passed := clickByClick(size, start, markers, rotations)
+		require.Equal(t, value, dial.Value)
+		for i, m := range dial.Markers {
+			require.Equal(t, markers[i], m.Position)
+			require.Equal(t, landed[i], m.Landed, "landed on %d, size %d", m.Position, size)
+			require.Equal(t, int64(passed[i]), m.Passed.Int64(), "passed %d, size %d", m.Position, size)
+		}
+		require.Equal(t, landed[2], dial.Strictzero)
+		require.Equal(t, int64(passed[2]), dial.Zero.Int64())
+	}
+}
+
+func TestDialWrapsOptions(t *testing.T) {
+	dial := main.NewDial(main.WithSize(10), main.WithStart(-3), main.WithMarkers(12))
+	require.Equal(t, 7, dial.Value)
+	require.Equal(t, 2, dial.Markers[0].Position)
+}
+
+func TestDialHistory(t *testing.T) {
+	dial := main.NewDial(main.WithHistory())
+	dial.Parse(strings.NewReader(exampleInput))
+
+	// Positions and zero counts from the problem description
+	ends := []int{82, 52, 0, 95, 55, 0, 99, 0, 14, 32}
+	passed := []int{1, 0, 1, 0, 1, 1, 0, 1, 0, 1}
+
+	require.Len(t, dial.History, len(ends))
+	start, total := 50, int64(0)
+	for i, step := range dial.History {
+		require.Equal(t, i+1, step.Line)
+		require.Equal(t, start, step.Start)
+		require.Equal(t, ends[i], step.End)
+		require.Equal(t, int64(passed[i]), step.Passed.Int64(), "step %d (%s)", i+1, step)
+		require.Equal(t, ends[i] == 0, step.Landed)
+		start = step.End
+		total += step.Passed.Int64()
+	}
+	require.Equal(t, dial.Zero.Int64(), total)
+	require.Equal(t, "L68", dial.History[0].String())
+
+	untraced := main.NewDial()
+	untraced.Parse(strings.NewReader(exampleInput))
+	require.Empty(t, untraced.History)
+}
+
+func TestDialApply(t *testing.T) {
+	parsed := main.NewDial()
+	parsed.Parse(strings.NewReader(exampleInput))
+
+	// Drive a dial from rotations without building any text
+	applied := main.NewDial()
+	for _, rot := range []main.Rotation{
+		main.Left(68), main.Left(30), main.Right(48), main.Left(5), main.Right(60),
+		main.Left(55), main.Left(1), main.Left(99), main.Right(14), main.Left(82),
+	} {
+		applied.Apply(rot)
+	}
+
+	require.Equal(t, parsed.Value, applied.Value)
+	require.Equal(t, parsed.Strictzero, applied.Strictzero)
+	require.Zero(t, parsed.Zero.Cmp(applied.Zero))
+}
+
+func TestParseRotations(t *testing.T) {
+	var got []main.Rotation
+	for rot := range main.ParseRotations(strings.NewReader("L68\nbogus\n\nR30\nL5")) {
+		got = append(got, rot)
+		if len(got) == 2 {
+			break
+		}
+	}
+	require.Equal(t, []main.Rotation{{Line: 1, Clicks: -68}, {Line: 4, Clicks: 30}}, got)
+
+	_, err := main.ParseRotation("X12")
+	require.Error(t, err)
+}
+
+func TestDialBigRotations(t *testing.T) {
+	// Each spin of about 10^20 clicks passes zero 10^18 times; the first
+	// ends back on 50 and the second 49 clicks short of it
+	dial := main.NewDial(main.WithMarkers(0, 50))
+	dial.Parse(strings.NewReader("R100000000000000000000\nL100000000000000000049"))
+
+	require.Equal(t, 1, dial.Value)
+	want, _ := new(big.Int).SetString("2000000000000000000", 10)
+	require.Zero(t, want.Cmp(dial.Zero), "got %s", dial.Zero)
+	require.Equal(t, 0, dial.Strictzero)
+
+	// Marker 50 is passed as often, counting the landing after the first spin
+	want.SetString("2000000000000000000", 10)
+	require.Equal(t, 1, dial.Markers[1].Landed)
+	require.Zero(t, want.Cmp(dial.Markers[1].Passed), "got %s", dial.Markers[1].Passed)
+
+	// Big rotations agree with the int path where both apply
+	for _, n := range []int64{0, 1, 49, 50, 51, 99, 100, 12345} {
+		for _, sign := range []int64{1, -1} {
+			small, huge := main.NewDial(), main.NewDial()
+			small.Apply(main.Rotation{Clicks: int(sign * n)})
+			huge.Apply(main.Rotation{Big: big.NewInt(sign * n)})
+			require.Equal(t, small.Value, huge.Value)
+			require.Equal(t, small.Strictzero, huge.Strictzero)
+			require.Zero(t, small.Zero.Cmp(huge.Zero), "rotation %d", sign*n)
+		}
+	}
+
+	// Ints near the limit fall back to big arithmetic rather than overflowing
+	edge := main.NewDial()
+	edge.Apply(main.Right(math.MaxInt))
+	want.SetUint64(math.MaxInt)
+	want.Add(want, big.NewInt(50))
+	want.Quo(want, big.NewInt(100))
+	require.Zero(t, want.Cmp(edge.Zero), "got %s", edge.Zero)
+}
+
+// bruteForcePlan tries every direction and number of extra full turns for
+// each leg, plus a trailing rotation of whole turns, returning the fewest
+// clicks that point at zero exactly zeros times, or -1.
+func bruteForcePlan(size, start int, targets []int, zeros int) int {
+	var legs [][2]int
+	pos := start
+	for _, target := range targets {
+		if target != pos {
+			right := ((target-pos)%size + size) % size
+			legs = append(legs, [2]int{right, right - size})
+		}
+		pos = target
+	}
+
+	best := -1
+	var search func(i int, rotations []int)
+	search = func(i int, rotations []int) {
+		if i == len(legs)+1 {
+			dial := main.NewDial(main.WithSize(size), main.WithStart(start))
+			clicks := 0
+			for _, n := range rotations {
+				dial.Apply(main.Rotation{Clicks: n})
+				clicks += max(n, -n)
+			}
+			if dial.Zero.Int64() == int64(zeros) && (best < 0 || clicks < best) {
+				best = clicks
+			}
+			return
+		}
+		for k := range zeros + 1 {
+			if i == len(legs) {
+				search(i+1, append(rotations, k*size))
+				continue
+			}
+			search(i+1, append(rotations, legs[i][0]+k*size))
+			search(i+1, append(rotations, legs[i][1]-k*size))
+		}
+	}
+	search(0, nil)
+	return best
+}
+
+func TestPlanRotations(t *testing.T) {
+	rng := rand.New(rand.NewPCG(35, 1))
+
+	for range 300 {
+		size := rng.IntN(8) + 1
+		start := rng.IntN(size)
+		targets := make([]int, rng.IntN(4))
+		for i := range targets {
+			targets[i] = rng.IntN(size)
+		}
+		zeros := rng.IntN(4)
+
+		plan, err := main.PlanRotations(size, start, targets, zeros)
+		want := bruteForcePlan(size, start, targets, zeros)
+		if want < 0 {
+			require.Error(t, err, "size %d start %d targets %v zeros %d", size, start, targets, zeros)
+			continue
+		}
+		require.NoError(t, err, "size %d start %d targets %v zeros %d", size, start, targets, zeros)
+		require.Equal(t, want, plan.Clicks, "size %d start %d targets %v zeros %d\n%s",
+			size, start, targets, zeros, plan)
+
+		// Replaying the plan visits every target in order
+		dial := main.NewDial(main.WithSize(size), main.WithStart(start), main.WithHistory())
+		dial.Parse(strings.NewReader(plan.String()))
+		require.Equal(t, int64(zeros), dial.Zero.Int64())
+
+		pos, step := start, 0
+		for _, target := range targets {
+			if target != pos {
+				require.Less(t, step, len(dial.History))
+				require.Equal(t, target, dial.History[step].End)
+				step++
+			}
+			pos = target
+		}
+	}
+}
+
+func TestPlanRotationsExample(t *testing.T) {
+	// The example's stops, reached the short way round
+	plan, err := main.PlanRotations(100, 50, []int{82, 52, 0, 95, 55, 0, 99, 0, 14, 32}, main.AnyZeros)
+	require.NoError(t, err)
+	require.Len(t, plan.Rotations, 10)
+	require.Equal(t, 32+30+48+5+40+45+1+1+14+18, plan.Clicks)
+	require.Equal(t, 3, plan.Zeros)
+}
+
+func TestLockOdometer(t *testing.T) {
+	values := func(l *main.Lock) []int {
+		var v []int
+		for _, d := range l.Dials {
+			v = append(v, d.Value)
+		}
+		return v
+	}
+
+	// Three decimal wheels count like an odometer
+	lock := main.NewLock(3, main.WithSize(10), main.WithStart(0))
+	lock.Parse(strings.NewReader("R123"))
+	require.Equal(t, []int{3, 2, 1}, values(lock))
+
+	lock.Parse(strings.NewReader("L4\n3:R9"))
+	require.Equal(t, []int{9, 1, 0}, values(lock))
+	require.Equal(t, int64(1), lock.Overflow.Int64())
+
+	// Borrowing from an all-zero lock wraps every wheel
+	lock = main.NewLock(3, main.WithSize(10), main.WithStart(0))
+	lock.Apply(0, main.Left(1))
+	require.Equal(t, []int{9, 9, 9}, values(lock))
+	require.Equal(t, int64(-1), lock.Overflow.Int64())
+}
+
+func TestLockSingleDialInput(t *testing.T) {
+	// Unaddressed input drives the first dial exactly like a lone Dial
+	lock := main.NewLock(2)
+	lock.Parse(strings.NewReader(exampleInput))
+	require.Equal(t, 32, lock.Dials[0].Value)
+	require.Equal(t, 3, lock.Dials[0].Strictzero)
+	require.Equal(t, int64(6), lock.Dials[0].Zero.Int64())
+
+	// The first dial wrapped left 4 times and right twice
+	require.Equal(t, 48, lock.Dials[1].Value)
+
+	// A lone Dial ignores instructions for other wheels
+	dial := main.NewDial()
+	dial.Parse(strings.NewReader("2:L15\n1:R5\nR5"))
+	require.Equal(t, 60, dial.Value)
+}
@@ -26,5 +26,5 @@ func TestExample(t *testing.T) {
 
 	require.Equal(t, 32, dial.Value)
 	require.Equal(t, 3, dial.Strictzero)
-	require.Equal(t, 6, dial.Zero)
+	require.Equal(t, int64(6), dial.Zero.Int64())
 }
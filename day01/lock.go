@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+)
+
+// Lock is a row of dials linked like an odometer: every time a dial wraps
+// past zero it turns the next dial one click in the same direction.
+// Turning right from 99 to 0 carries into the next dial, and turning left
+// from 0 to 99 borrows from it.
+type Lock struct {
+	Dials    []*Dial
+	Overflow *big.Int // revolutions carried out of the last dial
+}
+
+// NewLock returns a lock of n dials, each created with NewDial(opts...).
+func NewLock(n int, opts ...DialOption) *Lock {
+	l := &Lock{Dials: make([]*Dial, max(n, 1)), Overflow: new(big.Int)}
+	for i := range l.Dials {
+		l.Dials[i] = NewDial(opts...)
+	}
+	return l
+}
+
+// Parse reads rotation instructions from r. Instructions may name the dial
+// to turn, starting at 1, as in "2:L15"; unaddressed instructions turn the
+// first dial, so single dial input works unchanged. Instructions for dials
+// the lock doesn't have are skipped.
+func (l *Lock) Parse(r io.Reader) {
+	for rot := range ParseRotations(r) {
+		wheel := max(rot.Wheel, 1)
+		if wheel > len(l.Dials) {
+			continue
+		}
+		l.Apply(wheel-1, rot)
+	}
+}
+
+func (l *Lock) String() string {
+	var sb strings.Builder
+	for i, d := range l.Dials {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		fmt.Fprintf(&sb, "dial %d: %s", i+1, d)
+	}
+	fmt.Fprintf(&sb, "\noverflow: %d", l.Overflow)
+	return sb.String()
+}
+
+// Apply turns dial i (counting from 0) and carries any full revolutions
+// into the dials after it.
+func (l *Lock) Apply(i int, rot Rotation) {
+	if i < 0 || i >= len(l.Dials) {
+		return
+	}
+
+	for ; i < len(l.Dials); i++ {
+		d := l.Dials[i]
+
+		// Floor division counts wraps past zero: positive when turning
+		// right, negative when turning left
+		carry := new(big.Int).Add(rot.BigClicks(), big.NewInt(int64(d.Value)))
+		carry.Div(carry, big.NewInt(int64(d.Size)))
+
+		d.Apply(rot)
+		if carry.Sign() == 0 {
+			return
+		}
+
+		rot = Rotation{Line: rot.Line, Wheel: i + 2}
+		if carry.IsInt64() {
+			rot.Clicks = int(carry.Int64())
+		} else {
+			rot.Big = carry
+		}
+	}
+
+	l.Overflow.Add(l.Overflow, rot.BigClicks())
+}
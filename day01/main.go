@@ -1,84 +1,279 @@
 package main
 
 import (
-	"bufio"
 	"flag"
 	"fmt"
 	"io"
 	"log"
+	"math"
+	"math/big"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
+
+	"github.com/lcox74/aoc25/internal/report"
 )
 
-// Dial tracks a rotating dial that wraps at 0-99.
-// It counts how many times the dial passes through zero.
+// Dial tracks a rotating dial that wraps at 0 to Size-1.
+// It counts how many times the dial passes through zero,
+// and the same for any other tracked marker positions.
 type Dial struct {
 	Value      int
-	Strictzero int // times landed exactly on zero
-	Zero       int // times passed through zero
+	Size       int
+	Strictzero int      // times landed exactly on zero
+	Zero       *big.Int // times passed through zero
+	Markers    []Marker // tracked positions, zero by default
+	Trace      bool     // record every rotation in History
+	History    []Step   // rotations applied while Trace is set
+}
+
+// Marker counts how often the dial points at a tracked position.
+type Marker struct {
+	Position int
+	Landed   int      // times a rotation ended on Position
+	Passed   *big.Int // times the dial pointed at Position, during or at the end of a rotation
+}
+
+// DialOption configures a Dial created by NewDial.
+type DialOption func(*Dial)
+
+// WithSize sets the number of positions on the dial. Sizes below 1 are ignored.
+func WithSize(size int) DialOption {
+	return func(d *Dial) {
+		if size > 0 {
+			d.Size = size
+		}
+	}
+}
+
+// WithStart sets the position the dial starts at.
+func WithStart(value int) DialOption {
+	return func(d *Dial) {
+		d.Value = value
+	}
+}
+
+// WithMarkers replaces the tracked positions, which default to just zero.
+func WithMarkers(positions ...int) DialOption {
+	return func(d *Dial) {
+		d.Markers = make([]Marker, len(positions))
+		for i, pos := range positions {
+			d.Markers[i].Position = pos
+		}
+	}
+}
+
+// WithHistory records every rotation in the dial's History.
+func WithHistory() DialOption {
+	return func(d *Dial) {
+		d.Trace = true
+	}
 }
 
-func NewDial() *Dial {
-	return &Dial{Value: 50}
+// NewDial returns a 0-99 dial starting at 50 that tracks zero,
+// unless overridden by opts.
+func NewDial(opts ...DialOption) *Dial {
+	d := &Dial{Value: 50, Size: 100, Zero: new(big.Int), Markers: []Marker{{Position: 0}}}
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	// Keep the start and markers on the ring
+	d.Value = d.wrap(d.Value)
+	for i := range d.Markers {
+		d.Markers[i].Position = d.wrap(d.Markers[i].Position)
+		d.Markers[i].Passed = new(big.Int)
+	}
+
+	return d
 }
 
 // Parse reads rotation instructions from r.
 // Each line is a direction (L/R) followed by a number, e.g. "L68" or "R30".
 // L rotates left (counter-clockwise), R rotates right (clockwise).
+// Instructions addressed to another wheel of a Lock, e.g. "2:L15", are skipped.
 func (d *Dial) Parse(r io.Reader) {
-	scanner := bufio.NewScanner(r)
-	for scanner.Scan() {
-		line := scanner.Text()
-		if len(line) < 2 {
+	for rot := range ParseRotations(r) {
+		if rot.Wheel > 1 {
 			continue
 		}
+		d.Apply(rot)
+	}
+}
 
-		dir := line[0]
-		n, err := strconv.Atoi(line[1:])
-		if err != nil {
-			continue
-		}
+func (d *Dial) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "value: %d, part1: %d, part2: %d", d.Value, d.Strictzero, d.Zero)
 
-		switch dir {
-		case 'R':
-			d.rotate(n)
-		case 'L':
-			d.rotate(-n)
-		}
+	// The default zero marker is already reported as part1 and part2
+	if len(d.Markers) == 1 && d.Markers[0].Position == 0 {
+		return sb.String()
+	}
+	for _, m := range d.Markers {
+		fmt.Fprintf(&sb, "\n\tmarker %d: landed %d, passed %d", m.Position, m.Landed, m.Passed)
 	}
+	return sb.String()
 }
 
-func (d *Dial) String() string {
-	return fmt.Sprintf("value: %d, part1: %d, part2: %d", d.Value, d.Strictzero, d.Zero)
+// Apply turns the dial by a single rotation, updating the counts and,
+// if tracing, the history.
+func (d *Dial) Apply(rot Rotation) {
+	start := d.Value
+	var zero *big.Int
+	if d.Trace {
+		zero = new(big.Int).Set(d.Zero)
+	}
+
+	if rot.Big != nil {
+		d.rotateBig(rot.Big)
+	} else {
+		d.rotate(rot.Clicks)
+	}
+
+	if d.Trace {
+		d.History = append(d.History, Step{
+			Line:     rot.Line,
+			Rotation: rot.BigClicks(),
+			Start:    start,
+			End:      d.Value,
+			Passed:   zero.Sub(d.Zero, zero),
+			Landed:   d.Value == 0,
+		})
+	}
 }
 
 func (d *Dial) rotate(n int) {
-	// Count zero crossings based on direction
-	if n >= 0 {
-		d.Zero += (d.Value + n) / 100
-	} else {
-		d.Zero += ((100-d.Value)%100 - n) / 100
+	// Beyond this the arithmetic below could overflow
+	if limit := math.MaxInt - d.Size; n > limit || n < -limit {
+		d.rotateBig(big.NewInt(int64(n)))
+		return
+	}
+
+	// Count crossings based on direction
+	d.Zero.Add(d.Zero, big.NewInt(int64(d.passes(0, n))))
+	for i := range d.Markers {
+		m := &d.Markers[i]
+		m.Passed.Add(m.Passed, big.NewInt(int64(d.passes(m.Position, n))))
 	}
 
 	// Update value with wrap-around
-	d.Value = ((d.Value+n)%100 + 100) % 100
+	d.Value = d.wrap(d.Value + n)
+	d.land()
+}
+
+// rotateBig is rotate for rotations of any size.
+func (d *Dial) rotateBig(n *big.Int) {
+	d.Zero.Add(d.Zero, d.passesBig(0, n))
+	for i := range d.Markers {
+		m := &d.Markers[i]
+		m.Passed.Add(m.Passed, d.passesBig(m.Position, n))
+	}
+
+	// Only the remainder on the ring matters for the new value
+	rem := new(big.Int).Mod(n, big.NewInt(int64(d.Size)))
+	d.Value = d.wrap(d.Value + int(rem.Int64()))
+	d.land()
+}
+
+// land counts the dial stopping on zero or a marker.
+func (d *Dial) land() {
 	if d.Value == 0 {
 		d.Strictzero++
 	}
+	for i := range d.Markers {
+		if d.Value == d.Markers[i].Position {
+			d.Markers[i].Landed++
+		}
+	}
+}
+
+// passes counts how many times the dial points at pos while rotating n
+// clicks from its current value, including where it stops.
+func (d *Dial) passes(pos, n int) int {
+	// Measure the value relative to pos so pos behaves like zero
+	v := d.wrap(d.Value - pos)
+	if n >= 0 {
+		return (v + n) / d.Size
+	}
+	return ((d.Size-v)%d.Size - n) / d.Size
+}
+
+// passesBig is passes for rotations of any size.
+func (d *Dial) passesBig(pos int, n *big.Int) *big.Int {
+	v := d.wrap(d.Value - pos)
+	if n.Sign() < 0 {
+		v = (d.Size - v) % d.Size
+	}
+
+	count := new(big.Int).Abs(n)
+	count.Add(count, big.NewInt(int64(v)))
+	return count.Quo(count, big.NewInt(int64(d.Size)))
+}
+
+// wrap maps any value onto the ring 0 to Size-1.
+func (d *Dial) wrap(v int) int {
+	return (v%d.Size + d.Size) % d.Size
+}
+
+// parsePositions parses a comma separated list of dial positions.
+func parsePositions(s string) ([]int, error) {
+	var positions []int
+	for field := range strings.SplitSeq(s, ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		pos, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid position %q: %w", field, err)
+		}
+		positions = append(positions, pos)
+	}
+	return positions, nil
 }
 
 func main() {
-	var inputFile string
+	var inputFile, markers, trace, plan string
+	var reportRun bool
+	var size, start, zeros, dials int
 
 	flag.StringVar(&inputFile, "input", "day01/input.txt", "input file path")
 	flag.StringVar(&inputFile, "i", "day01/input.txt", "input file path (shorthand)")
+	flag.IntVar(&size, "size", 100, "number of positions on the dial")
+	flag.IntVar(&start, "start", 50, "position the dial starts at")
+	flag.IntVar(&dials, "dials", 1, "number of linked dials, addressed as 2:L15")
+	flag.StringVar(&markers, "markers", "0", "comma separated positions to track")
+	flag.StringVar(&trace, "trace", "", "print every rotation as a table or json")
+	flag.StringVar(&plan, "plan", "", "print the shortest rotations visiting these comma separated positions")
+	flag.IntVar(&zeros, "zeros", AnyZeros, "with -plan, the exact number of times to point at zero")
+	flag.BoolVar(&reportRun, "report", false, "print a JSON run record for the aoc report")
 	flag.Parse()
 
+	if plan != "" {
+		targets, err := parsePositions(plan)
+		if err != nil {
+			log.Fatal(err)
+		}
+		rotations, err := PlanRotations(size, start, targets, zeros)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Print(rotations)
+		return
+	}
+
 	// Validate input file
 	if inputFile == "" {
 		log.Fatal("no input file specified")
 	}
+	if dials > 1 && (trace != "" || reportRun) {
+		log.Fatal("-trace and -report need a single dial")
+	}
+
+	positions, err := parsePositions(markers)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	// Open the input file
 	f, err := os.Open(filepath.Clean(inputFile))
@@ -88,7 +283,48 @@ func main() {
 	defer f.Close()
 
 	// Process the dial instructions
-	dial := NewDial()
+	opts := []DialOption{WithSize(size), WithStart(start), WithMarkers(positions...)}
+	if trace != "" {
+		opts = append(opts, WithHistory())
+	}
+
+	if dials > 1 {
+		lock := NewLock(dials, opts...)
+		lock.Parse(f)
+		fmt.Println(lock)
+		return
+	}
+
+	dial := NewDial(opts...)
+	if reportRun {
+		run, err := report.Measure(1, f, dial.Parse)
+		if err != nil {
+			log.Fatal(err)
+		}
+		run.Answers = []string{strconv.Itoa(dial.Strictzero), dial.Zero.String()}
+		if err := run.Write(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	dial.Parse(f)
-	fmt.Println(dial)
+
+	switch trace {
+	case "":
+	case "table":
+		err = dial.WriteTraceTable(os.Stdout)
+	case "json":
+		err = dial.WriteTraceJSON(os.Stdout)
+	default:
+		err = fmt.Errorf("unknown trace format %q", trace)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Keep JSON output machine readable
+	if trace != "json" {
+		fmt.Println(dial)
+	}
 }
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+// AnyZeros lets PlanRotations pass zero any number of times.
+const AnyZeros = -1
+
+// Plan is a sequence of rotations that visits target positions in order.
+type Plan struct {
+	Rotations []Rotation
+	Clicks    int // total clicks turned
+	Zeros     int // times the dial points at zero, as counted by Dial.Zero
+}
+
+// PlanRotations is the inverse of Dial: it finds the rotations with the
+// fewest total clicks that take a dial of the given size from start to each
+// target in turn, pointing at zero exactly zeros times (or any number of
+// times for AnyZeros). A target equal to the current position needs no
+// rotation.
+//
+// Each leg between targets is one rotation in either direction, and an extra
+// full turn on any leg adds exactly one zero pass for size clicks. So the
+// cheapest plan never splits a leg, which also makes it the plan with the
+// fewest instructions.
+func PlanRotations(size, start int, targets []int, zeros int) (Plan, error) {
+	if size < 1 {
+		return Plan{}, errors.New("plan: size must be positive")
+	}
+	wrap := func(v int) int { return (v%size + size) % size }
+
+	// Each leg can go right or left; legs that don't move are skipped
+	type option struct{ clicks, zeros int }
+	var legs [][2]option
+	pos := wrap(start)
+	for _, target := range targets {
+		target = wrap(target)
+		if target == pos {
+			continue
+		}
+
+		right := wrap(target - pos)
+		left := size - right
+		legs = append(legs, [2]option{
+			{right, (pos + right) / size},
+			{-left, ((size-pos)%size + left) / size},
+		})
+		pos = target
+	}
+
+	// best[i][z] is the fewest clicks for the first i legs passing zero z
+	// times, with choice recording the direction taken to get there
+	best := make([][]int, len(legs)+1)
+	choice := make([][]int, len(legs)+1)
+	for i := range best {
+		best[i] = make([]int, len(legs)+1)
+		choice[i] = make([]int, len(legs)+1)
+		for z := range best[i] {
+			best[i][z] = math.MaxInt
+		}
+	}
+	best[0][0] = 0
+
+	for i, leg := range legs {
+		for z, clicks := range best[i] {
+			if clicks == math.MaxInt {
+				continue
+			}
+			for dir, opt := range leg {
+				total := clicks + abs(opt.clicks)
+				if total < best[i+1][z+opt.zeros] {
+					best[i+1][z+opt.zeros] = total
+					choice[i+1][z+opt.zeros] = dir
+				}
+			}
+		}
+	}
+
+	// Pick the pass count to end on, topping up with full turns
+	endZeros, extra := -1, 0
+	cost := math.MaxInt
+	for z, clicks := range best[len(legs)] {
+		if clicks == math.MaxInt || (zeros != AnyZeros && z > zeros) {
+			continue
+		}
+		turns := 0
+		if zeros != AnyZeros {
+			turns = zeros - z
+		}
+		if total := clicks + turns*size; total < cost {
+			endZeros, extra, cost = z, turns, total
+		}
+	}
+	if endZeros < 0 {
+		return Plan{}, errors.New("plan: too few zero passes for these targets")
+	}
+
+	// Walk the choices back from the end
+	plan := Plan{Clicks: cost, Zeros: endZeros + extra}
+	plan.Rotations = make([]Rotation, len(legs))
+	z := endZeros
+	for i := len(legs); i > 0; i-- {
+		opt := legs[i-1][choice[i][z]]
+		plan.Rotations[i-1] = Rotation{Clicks: opt.clicks}
+		z -= opt.zeros
+	}
+
+	// Full turns go onto the last rotation, or a rotation of their own
+	if extra > 0 {
+		if len(plan.Rotations) == 0 {
+			plan.Rotations = append(plan.Rotations, Right(0))
+		}
+		last := &plan.Rotations[len(plan.Rotations)-1]
+		if last.Clicks < 0 {
+			last.Clicks -= extra * size
+		} else {
+			last.Clicks += extra * size
+		}
+	}
+
+	return plan, nil
+}
+
+// String formats the plan in the input format that Dial.Parse accepts.
+func (p Plan) String() string {
+	var sb strings.Builder
+	for _, rot := range p.Rotations {
+		sb.WriteString(rot.String())
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"math/big"
+	"strconv"
+)
+
+// Rotation is a single instruction to turn the dial. Rotations too large
+// for an int set Big instead of Clicks.
+type Rotation struct {
+	Line   int      // input line number starting at 1, or 0 when not read from input
+	Wheel  int      // dial of a Lock to turn, starting at 1, or 0 when unaddressed
+	Clicks int      // clicks to turn, negative for left (counter-clockwise)
+	Big    *big.Int // clicks to turn when set, overriding Clicks
+}
+
+// Left returns a rotation of n clicks counter-clockwise.
+func Left(n int) Rotation {
+	return Rotation{Clicks: -n}
+}
+
+// Right returns a rotation of n clicks clockwise.
+func Right(n int) Rotation {
+	return Rotation{Clicks: n}
+}
+
+// ParseRotation parses a single instruction such as "L68" or "R30",
+// optionally addressed to a wheel of a Lock as in "2:L15".
+func ParseRotation(s string) (Rotation, error) {
+	rot, ok := parseRotation([]byte(s))
+	if !ok {
+		return Rotation{}, fmt.Errorf("invalid rotation %q", s)
+	}
+	return rot, nil
+}
+
+// parseRotation parses an instruction without allocating, so that streaming
+// a large input stays cheap.
+func parseRotation(b []byte) (Rotation, bool) {
+	wheel := 0
+	if i := bytes.IndexByte(b, ':'); i >= 0 {
+		w, err := strconv.Atoi(string(b[:i]))
+		if err != nil || w < 1 {
+			return Rotation{}, false
+		}
+		wheel, b = w, b[i+1:]
+	}
+
+	rot, ok := parseClicks(b)
+	rot.Wheel = wheel
+	return rot, ok
+}
+
+// parseClicks parses the direction and clicks of an instruction.
+func parseClicks(b []byte) (Rotation, bool) {
+	if len(b) < 2 {
+		return Rotation{}, false
+	}
+
+	if b[0] != 'R' && b[0] != 'L' {
+		return Rotation{}, false
+	}
+
+	n, err := strconv.Atoi(string(b[1:]))
+	if errors.Is(err, strconv.ErrRange) {
+		return parseBigRotation(b)
+	}
+	if err != nil {
+		return Rotation{}, false
+	}
+
+	if b[0] == 'L' {
+		return Left(n), true
+	}
+	return Right(n), true
+}
+
+// parseBigRotation parses an instruction whose clicks overflow an int.
+func parseBigRotation(b []byte) (Rotation, bool) {
+	n, ok := new(big.Int).SetString(string(b[1:]), 10)
+	if !ok {
+		return Rotation{}, false
+	}
+
+	if b[0] == 'L' {
+		n.Neg(n)
+	}
+	return Rotation{Big: n}, true
+}
+
+// ParseRotations yields the rotations read from r, one per line,
+// skipping lines that aren't valid instructions.
+func ParseRotations(r io.Reader) iter.Seq[Rotation] {
+	return func(yield func(Rotation) bool) {
+		scanner := bufio.NewScanner(r)
+		lineNum := 0
+		for scanner.Scan() {
+			lineNum++
+			rot, ok := parseRotation(scanner.Bytes())
+			if !ok {
+				continue
+			}
+
+			rot.Line = lineNum
+			if !yield(rot) {
+				return
+			}
+		}
+	}
+}
+
+// BigClicks returns the clicks to turn as a new big.Int.
+func (r Rotation) BigClicks() *big.Int {
+	if r.Big != nil {
+		return new(big.Int).Set(r.Big)
+	}
+	return big.NewInt(int64(r.Clicks))
+}
+
+// String formats the rotation as an input instruction, e.g. "L68" or "2:L15".
+func (r Rotation) String() string {
+	prefix := ""
+	if r.Wheel > 0 {
+		prefix = strconv.Itoa(r.Wheel) + ":"
+	}
+
+	n := r.BigClicks()
+	if n.Sign() < 0 {
+		return prefix + "L" + n.Neg(n).String()
+	}
+	return prefix + "R" + n.String()
+}
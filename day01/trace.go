@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"math/big"
+	"slices"
+	"text/tabwriter"
+)
+
+// Step records a single rotation applied to a traced dial.
+type Step struct {
+	Line     int      `json:"line"`     // input line number, starting at 1
+	Rotation *big.Int `json:"rotation"` // clicks turned, negative for left
+	Start    int      `json:"start"`    // position before the rotation
+	End      int      `json:"end"`      // position after the rotation
+	Passed   *big.Int `json:"passed"`   // times zero was passed, including landing on it
+	Landed   bool     `json:"landed"`   // rotation ended on zero
+}
+
+// String formats the rotation as an input instruction, e.g. "L68".
+func (s Step) String() string {
+	return Rotation{Big: s.Rotation}.String()
+}
+
+// Steps iterates over the recorded rotations in order.
+func (d *Dial) Steps() iter.Seq[Step] {
+	return slices.Values(d.History)
+}
+
+// WriteTraceTable prints the recorded rotations as an aligned table.
+func (d *Dial) WriteTraceTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "LINE\tROTATION\tSTART\tEND\tPASSED\tLANDED\t")
+	for step := range d.Steps() {
+		fmt.Fprintf(tw, "%d\t%s\t%d\t%d\t%s\t%t\t\n",
+			step.Line, step, step.Start, step.End, step.Passed, step.Landed)
+	}
+	return tw.Flush()
+}
+
+// WriteTraceJSON prints the recorded rotations as a JSON array.
+func (d *Dial) WriteTraceJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(d.History)
+}
@@ -0,0 +1,41 @@
+package main_test
+
+import (
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	main "github.com/lcox74/aoc25/day02"
+	"github.com/lcox74/aoc25/internal/report"
+)
+
+// budget for parsing and solving generatedInput, about 25% over measured.
+var budget = report.Budget{Allocs: 660, Bytes: 32_000}
+
+// generatedInput returns 50 ranges of up to 100,000 IDs with 4 to 10 digits.
+func generatedInput() string {
+	rng := rand.New(rand.NewPCG(29, 2))
+
+	ranges := make([]string, 50)
+	for i := range ranges {
+		start := rng.Int64N(9_999_900_000) + 1000
+		ranges[i] = fmt.Sprintf("%d-%d", start, start+rng.Int64N(100_000))
+	}
+	return strings.Join(ranges, ",")
+}
+
+func TestAllocBudget(t *testing.T) {
+	budget.Check(t, generatedInput(), func(r io.Reader) {
+		main.NewGiftShop().Parse(r)
+	})
+}
+
+func BenchmarkParse(b *testing.B) {
+	input := generatedInput()
+	b.ReportAllocs()
+	for b.Loop() {
+		main.NewGiftShop().Parse(strings.NewReader(input))
+	}
+}
@@ -1,6 +1,10 @@
 package main
 
 import (
+	"fmt"
+	"math/big"
+	"math/rand/v2"
+	"strconv"
 	"strings"
 	"testing"
 )
@@ -15,10 +19,365 @@ func TestExample(t *testing.T) {
 	shop := NewGiftShop()
 	shop.Parse(strings.NewReader(exampleInput))
 
-	if shop.InvalidSum1 != expectedPart1 {
+	if shop.InvalidSum1.Int64() != expectedPart1 {
 		t.Errorf("Part 1: expected %d, got %d", expectedPart1, shop.InvalidSum1)
 	}
-	if shop.InvalidSum2 != expectedPart2 {
+	if shop.InvalidSum2.Int64() != expectedPart2 {
 		t.Errorf("Part 2: expected %d, got %d", expectedPart2, shop.InvalidSum2)
 	}
 }
+
+// isRepeated reports whether the digits s are some pattern repeated,
+// exactly twice or at least twice, by comparing strings.
+func isRepeated(s string, atLeastTwice bool) bool {
+	for patternLen := 1; patternLen <= len(s)/2; patternLen++ {
+		if len(s)%patternLen != 0 || (!atLeastTwice && patternLen*2 != len(s)) {
+			continue
+		}
+		if strings.Repeat(s[:patternLen], len(s)/patternLen) == s {
+			return true
+		}
+	}
+	return false
+}
+
+func TestClosedFormMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewPCG(37, 2))
+	shop := NewGiftShop()
+
+	for range 200 {
+		start := rng.Int64N(2_000_000) + 1
+		end := start + rng.Int64N(20_000)
+
+		var want1, want2 int64
+		var ids2 []int64
+		for id := start; id <= end; id++ {
+			if isRepeated(strconv.FormatInt(id, 10), false) {
+				want1 += id
+			}
+			if isRepeated(strconv.FormatInt(id, 10), true) {
+				want2 += id
+				ids2 = append(ids2, id)
+			}
+		}
+
+		lo, hi := big.NewInt(start), big.NewInt(end)
+		if _, got := shop.exactlyTwice(lo, hi); got.Int64() != want1 {
+			t.Errorf("%d-%d exactly twice: expected %d, got %d", start, end, want1, got)
+		}
+		count, got := shop.atLeastTwice(lo, hi)
+		if got.Int64() != want2 || count.Int64() != int64(len(ids2)) {
+			t.Errorf("%d-%d at least twice: expected %d of %d, got %d of %d", start, end, len(ids2), want2, count, got)
+		}
+		if got := shop.findInvalidIDsInRange(lo, hi, true, 0); len(got) != len(ids2) {
+			t.Errorf("%d-%d ids: expected %v, got %v", start, end, ids2, got)
+		}
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		id          int64
+		unit        int64
+		repetitions int
+	}{
+		{11, 1, 2},
+		{1111, 1, 4},
+		{1212, 12, 2},
+		{123123123, 123, 3},
+		{1188511885, 11885, 2},
+		{1234, 1234, 1},
+		{7, 7, 1},
+	}
+
+	for _, tt := range tests {
+		unit, reps := Classify(big.NewInt(tt.id))
+		if unit.Int64() != tt.unit || reps != tt.repetitions {
+			t.Errorf("Classify(%d): expected %d x%d, got %d x%d", tt.id, tt.unit, tt.repetitions, unit, reps)
+		}
+	}
+}
+
+func TestRepetitionMatchesBruteForce(t *testing.T) {
+	queries := []Repetition{
+		{},
+		{Exactly: 2},
+		{Exactly: 3},
+		{AtLeast: 3},
+		{UnitLen: 2},
+		{AtLeast: 2, UnitLen: 1},
+	}
+	ranges := [][2]int64{{1, 150_000}, {90_000, 130_000}, {111_111, 111_111}}
+
+	for _, q := range queries {
+		for _, r := range ranges {
+			var wantCount, wantSum int64
+			for id := r[0]; id <= r[1]; id++ {
+				if q.Matches(big.NewInt(id)) {
+					wantCount++
+					wantSum += id
+				}
+			}
+
+			lo, hi := big.NewInt(r[0]), big.NewInt(r[1])
+			if got := q.Count(lo, hi); got.Int64() != wantCount {
+				t.Errorf("%+v over %d-%d: expected count %d, got %d", q, r[0], r[1], wantCount, got)
+			}
+			if got := q.Sum(lo, hi); got.Int64() != wantSum {
+				t.Errorf("%+v over %d-%d: expected sum %d, got %d", q, r[0], r[1], wantSum, got)
+			}
+		}
+	}
+
+	shop := NewGiftShop()
+	shop.Parse(strings.NewReader(exampleInput))
+	if _, sum := shop.Query(Repetition{AtLeast: 2}); sum.Cmp(shop.InvalidSum2) != 0 {
+		t.Errorf("AtLeast 2: expected part 2 sum %d, got %d", shop.InvalidSum2, sum)
+	}
+}
+
+func TestBases(t *testing.T) {
+	for _, base := range []int{2, 3, 16, 36} {
+		shop := NewGiftShop()
+		shop.Base = base
+		shop.Parse(strings.NewReader(strconv.FormatInt(1, base) + "-" + strconv.FormatInt(300_000, base)))
+
+		var want1, want2 int64
+		for id := int64(1); id <= 300_000; id++ {
+			if isRepeated(strconv.FormatInt(id, base), false) {
+				want1 += id
+			}
+			if isRepeated(strconv.FormatInt(id, base), true) {
+				want2 += id
+			}
+		}
+
+		if shop.InvalidSum1.Int64() != want1 || shop.InvalidSum2.Int64() != want2 {
+			t.Errorf("base %d: expected %d, %d, got %d, %d", base, want1, want2, shop.InvalidSum1, shop.InvalidSum2)
+		}
+		if _, sum := shop.Query(Repetition{}); sum.Int64() != want2 {
+			t.Errorf("base %d: expected query sum %d, got %d", base, want2, sum)
+		}
+	}
+
+	shop := NewGiftShop()
+	shop.Base = 16
+	if got := shop.Format(big.NewInt(0x1b1b)); got != "1b1b (6939)" {
+		t.Errorf("Format: expected 1b1b (6939), got %s", got)
+	}
+}
+
+func TestOverlappingRanges(t *testing.T) {
+	shop := NewGiftShop()
+	shop.Parse(strings.NewReader("95-115,100-120,110-111,121-130,1000-1100"))
+
+	// 99, 111 and 1010, with 111 in three ranges counted once
+	if got := shop.InvalidSum2.Int64(); got != 99+111+1010 {
+		t.Errorf("expected sum %d, got %d", 99+111+1010, got)
+	}
+
+	var merged, overlaps []string
+	for _, r := range shop.Merged {
+		merged = append(merged, r[0].String()+"-"+r[1].String())
+	}
+	for _, r := range shop.Overlaps {
+		overlaps = append(overlaps, r[0].String()+"-"+r[1].String())
+	}
+	if got := strings.Join(merged, ","); got != "95-130,1000-1100" {
+		t.Errorf("merged: expected 95-130,1000-1100, got %s", got)
+	}
+	if got := strings.Join(overlaps, ","); got != "100-115" {
+		t.Errorf("overlaps: expected 100-115, got %s", got)
+	}
+}
+
+func TestLargeIDs(t *testing.T) {
+	// 21 digits, past uint64, around 123 repeated 7 times
+	center, _ := new(big.Int).SetString("123123123123123123123", 10)
+	start := new(big.Int).Sub(center, big.NewInt(5000))
+	end := new(big.Int).Add(center, big.NewInt(5000))
+
+	want2 := new(big.Int)
+	for id := new(big.Int).Set(start); id.Cmp(end) <= 0; id.Add(id, big.NewInt(1)) {
+		if isRepeated(id.String(), true) {
+			want2.Add(want2, id)
+		}
+	}
+
+	shop := NewGiftShop()
+	shop.Parse(strings.NewReader(start.String() + "-" + end.String() + ",12345678901234567890-12345678901234567899"))
+
+	want2.Add(want2, big.NewInt(0).SetUint64(12345678901234567890))
+	if shop.InvalidSum1.String() != "12345678901234567890" {
+		t.Errorf("Part 1: expected 12345678901234567890, got %s", shop.InvalidSum1)
+	}
+	if shop.InvalidSum2.Cmp(want2) != 0 {
+		t.Errorf("Part 2: expected %s, got %s", want2, shop.InvalidSum2)
+	}
+}
+
+func TestRangeReports(t *testing.T) {
+	shop := NewGiftShop()
+	shop.Report = true
+	shop.ListIDs = 1
+	shop.Parse(strings.NewReader("95-115,998-1012"))
+
+	r := shop.Reports[0]
+	if r.Start.Int64() != 95 || r.End.Int64() != 115 {
+		t.Fatalf("expected range 95-115, got %s-%s", r.Start, r.End)
+	}
+	part2 := r.Rules[1]
+	if part2.Rule != "part2" || part2.Count.Int64() != 2 || part2.Sum.Int64() != 99+111 || len(part2.IDs) != 1 {
+		t.Errorf("expected part2 with 2 IDs summing to 210 and 1 listed, got %+v", part2)
+	}
+
+	var table, csv strings.Builder
+	if err := shop.WriteReportTable(&table); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(table.String(), "95-115    part2  2      210   99 ...") {
+		t.Errorf("unexpected table:\n%s", table.String())
+	}
+	if err := shop.WriteReportCSV(&csv); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(csv.String(), "998,1012,part2,2,2009,999 ...\n") {
+		t.Errorf("unexpected csv:\n%s", csv.String())
+	}
+
+	// Reports are only built on request, and the sums don't change
+	plain := NewGiftShop()
+	plain.Parse(strings.NewReader("95-115,998-1012"))
+	if plain.Reports != nil || plain.InvalidSum2.Cmp(shop.InvalidSum2) != 0 {
+		t.Errorf("expected no reports and part 2 %s, got %d reports and %s", shop.InvalidSum2, len(plain.Reports), plain.InvalidSum2)
+	}
+}
+
+// checked hides a rule's generator so it is checked one ID at a time.
+type checked struct{ Rule }
+
+func TestRulesMatchGenerators(t *testing.T) {
+	shop := NewGiftShop()
+	shop.Report = true
+	shop.ListIDs = 5
+	shop.Rules = []Rule{checked{exactlyTwice{}}, checked{atLeastTwice{}}}
+	shop.Parse(strings.NewReader(exampleInput + ",1-200000"))
+
+	if shop.RuleSums[0].Cmp(shop.InvalidSum1) != 0 || shop.RuleSums[1].Cmp(shop.InvalidSum2) != 0 {
+		t.Errorf("expected %s, %s, got %s, %s", shop.InvalidSum1, shop.InvalidSum2, shop.RuleSums[0], shop.RuleSums[1])
+	}
+	for _, r := range shop.Reports {
+		for i := range 2 {
+			fast, slow := r.Rules[i], r.Rules[i+2]
+			if fast.Count.Cmp(slow.Count) != 0 || fmt.Sprint(fast.IDs) != fmt.Sprint(slow.IDs) {
+				t.Errorf("%s-%s %s: expected %s %v, got %s %v", r.Start, r.End, fast.Rule, slow.Count, slow.IDs, fast.Count, fast.IDs)
+			}
+		}
+	}
+}
+
+func TestRules(t *testing.T) {
+	match, err := NewMatch("^1.*1$")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		rule    Rule
+		name    string
+		invalid []string
+		valid   []string
+	}{
+		{Palindrome{}, "palindrome", []string{"7", "121", "1221"}, []string{"12", "1231"}},
+		{Run{N: 3}, "run3", []string{"111", "12333", "1222a"}, []string{"1122", "12"}},
+		{DigitSum{K: 3}, "digitsum3", []string{"147", "3", "a2"}, []string{"1", "100"}},
+		{match, "match ^1.*1$", []string{"11", "1231"}, []string{"21", "112"}},
+	}
+
+	for _, tt := range tests {
+		if tt.rule.Name() != tt.name {
+			t.Errorf("expected name %q, got %q", tt.name, tt.rule.Name())
+		}
+		for _, digits := range tt.invalid {
+			if !tt.rule.Invalid(digits) {
+				t.Errorf("%s: expected %s to be invalid", tt.name, digits)
+			}
+		}
+		for _, digits := range tt.valid {
+			if tt.rule.Invalid(digits) {
+				t.Errorf("%s: expected %s to be valid", tt.name, digits)
+			}
+		}
+	}
+
+	// 1-9, 11-99 and 101-191
+	shop := NewGiftShop()
+	shop.Report = true
+	shop.Rules = []Rule{Palindrome{}}
+	shop.Parse(strings.NewReader("1-200"))
+	if got := shop.Reports[0].Rules[2].Count.Int64(); got != 9+9+10 {
+		t.Errorf("palindromes: expected %d, got %d", 9+9+10, got)
+	}
+	if !strings.HasSuffix(shop.String(), ", palindrome: "+shop.RuleSums[0].String()) {
+		t.Errorf("expected palindrome sum in %q", shop.String())
+	}
+}
+
+func TestUint64MatchesBig(t *testing.T) {
+	rng := rand.New(rand.NewPCG(40, 2))
+
+	for _, base := range []int{2, 3, 10, 16, 36} {
+		// The largest power of base that fits, so IDs below it always do
+		limit := uint64(1)
+		for n := 1; ; n++ {
+			next, ok := pow64(base, n)
+			if !ok {
+				break
+			}
+			limit = next
+		}
+
+		for range 200 {
+			start := rng.Uint64N(limit)
+			end := start + rng.Uint64N(limit-start)
+			if rng.IntN(2) == 0 {
+				// Short ranges as well, so most have no invalid IDs
+				end = start + min(rng.Uint64N(1000), limit-1-start)
+			}
+			bigStart, bigEnd := new(big.Int).SetUint64(start), new(big.Int).SetUint64(end)
+
+			for numDigits := digitLength64(start, base); numDigits <= digitLength64(end, base); numDigits++ {
+				for patternLen := 1; patternLen <= numDigits/2; patternLen++ {
+					if numDigits%patternLen != 0 {
+						continue
+					}
+					count, sum := withPeriod64(start, end, numDigits, patternLen, base)
+					wantCount, wantSum := withPeriod(bigStart, bigEnd, numDigits, patternLen, base)
+					if count != wantCount.Uint64() || sum.big().Cmp(wantSum) != 0 {
+						t.Fatalf("base %d, %d-%d, %d digits, period %d: got %d, %s, want %s, %s",
+							base, start, end, numDigits, patternLen, count, sum.big(), wantCount, wantSum)
+					}
+				}
+			}
+		}
+	}
+
+	// Ranges just past uint64 digits take the big.Int path but add no
+	// invalid IDs, so they must match the uint64 path up to the limit
+	for _, tc := range []struct {
+		base       int
+		limit, end string
+	}{
+		{10, "9999999999999999999", "10000000000000000005"},
+		{2, "111111111111111111111111111111111111111111111111111111111111111", "1000000000000000000000000000000000000000000000000000000000000101"},
+	} {
+		small, large := NewGiftShop(), NewGiftShop()
+		small.Base, large.Base = tc.base, tc.base
+		small.Parse(strings.NewReader("1-" + tc.limit))
+		large.Parse(strings.NewReader("1-" + tc.end))
+		if small.InvalidSum1.Cmp(large.InvalidSum1) != 0 || small.InvalidSum2.Cmp(large.InvalidSum2) != 0 {
+			t.Errorf("base %d: uint64 path got %s, %s, big path got %s, %s", tc.base,
+				small.InvalidSum1, small.InvalidSum2, large.InvalidSum1, large.InvalidSum2)
+		}
+	}
+}
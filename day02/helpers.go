@@ -1,52 +1,113 @@
 package main
 
-import "strconv"
+import (
+	"math/big"
+	"strings"
+)
 
-// pow10 returns 10^n as an int64.
-func pow10(n int) int64 {
-	result := int64(1)
-	for range n {
-		result *= 10
-	}
-	return result
+// pow returns base^n.
+func pow(base, n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(int64(base)), big.NewInt(int64(n)), nil)
 }
 
 // patternBounds returns the min and max values for a number with the given digit length.
-// For length 1: returns (1, 9)
+// In base 10, for length 1: returns (1, 9)
 // For length 2: returns (10, 99)
 // etc.
-func patternBounds(length int) (minVal, maxVal int64) {
-	if length == 1 {
-		return 1, 9
-	}
-
-	return pow10(length - 1), pow10(length) - 1
+func patternBounds(length, base int) (minVal, maxVal *big.Int) {
+	maxVal = pow(base, length)
+	return pow(base, length-1), maxVal.Sub(maxVal, big.NewInt(1))
 }
 
 // buildRepeatedID constructs a number by repeating a pattern a given number of times.
-// For example, buildRepeatedID(12, 2, 3) returns 121212.
-func buildRepeatedID(pattern int64, patternLen, repetitions int) int64 {
-	var result, multiplier int64 = 0, 1
+// For example, buildRepeatedID(12, 2, 3, 10) returns 121212.
+func buildRepeatedID(pattern *big.Int, patternLen, repetitions, base int) *big.Int {
+	result := new(big.Int)
+	shift := pow(base, patternLen)
 
 	// Repeat the pattern the specified number of times
 	for range repetitions {
-		result += pattern * multiplier
-		multiplier *= pow10(patternLen)
+		result.Mul(result, shift)
+		result.Add(result, pattern)
 	}
 
 	return result
 }
 
-// digitLength returns the number of digits in n.
-func digitLength(n int64) int {
-	return len(strconv.FormatInt(n, 10))
+// digitLength returns the number of digits in n written in base.
+func digitLength(n *big.Int, base int) int {
+	return len(n.Text(base))
+}
+
+// seriesSum returns first + (first+1) + ... + last.
+func seriesSum(first, last *big.Int) *big.Int {
+	n := new(big.Int).Sub(last, first)
+	n.Add(n, big.NewInt(1))
+
+	sum := new(big.Int).Add(first, last)
+	sum.Mul(sum, n)
+	return sum.Rsh(sum, 1)
+}
+
+// ceilDiv returns a/b rounded up, for positive b.
+func ceilDiv(a, b *big.Int) *big.Int {
+	q, m := new(big.Int).DivMod(a, b, new(big.Int))
+	if m.Sign() != 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	return q
+}
+
+// mobius returns the Möbius function of n: 0 if n has a squared prime
+// factor, otherwise 1 or -1 for an even or odd number of prime factors.
+func mobius(n int) int {
+	mu := 1
+	for p := 2; p*p <= n; p++ {
+		if n%p != 0 {
+			continue
+		}
+		n /= p
+		if n%p == 0 {
+			return 0
+		}
+		mu = -mu
+	}
+	if n > 1 {
+		mu = -mu
+	}
+	return mu
+}
+
+// smallestPeriod returns the length of the shortest unit that digits is made
+// of repeated, which is len(digits) if it doesn't repeat.
+func smallestPeriod(digits string) int {
+	for unitLen := 1; unitLen <= len(digits)/2; unitLen++ {
+		if len(digits)%unitLen == 0 && strings.Repeat(digits[:unitLen], len(digits)/unitLen) == digits {
+			return unitLen
+		}
+	}
+	return len(digits)
+}
+
+// isPrimitivePattern reports whether a pattern is not itself a shorter
+// pattern repeated, e.g. 1212 is not primitive.
+func isPrimitivePattern(pattern *big.Int, base int) bool {
+	digits := pattern.Text(base)
+	return smallestPeriod(digits) == len(digits)
+}
+
+// minBig returns the smaller of a and b.
+func minBig(a, b *big.Int) *big.Int {
+	if a.Cmp(b) <= 0 {
+		return a
+	}
+	return b
 }
 
-// sum returns the sum of a slice of numbers.
-func sum[T ~int | ~int64 | ~float64](nums []T) T {
-	var s T
-	for _, n := range nums {
-		s += n
+// maxBig returns the larger of a and b.
+func maxBig(a, b *big.Int) *big.Int {
+	if a.Cmp(b) >= 0 {
+		return a
 	}
-	return s
+	return b
 }
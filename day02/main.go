@@ -6,29 +6,48 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"math/big"
 	"os"
 	"path/filepath"
-	"strconv"
+	"slices"
 	"strings"
+
+	"github.com/lcox74/aoc25/internal/report"
 )
 
 // GiftShop checks product ID ranges for invalid IDs.
 // Part 1: Invalid IDs are numbers made of a digit sequence repeated exactly twice (e.g., 55, 6464, 123123).
 // Part 2: Invalid IDs are numbers made of a digit sequence repeated at least twice.
+// Digits are read in Base, from 2 to 36, so 0x1b1b repeats in base 16.
+// IDs may have any number of digits.
 type GiftShop struct {
-	Ranges      [][2]int64
-	InvalidSum1 int64 // Part 1: exactly twice
-	InvalidSum2 int64 // Part 2: at least twice
-	Verbose     bool
+	Ranges      [][2]*big.Int // as read, possibly overlapping
+	Merged      [][2]*big.Int // Ranges in order with overlapping ranges joined
+	Overlaps    [][2]*big.Int // IDs covered by more than one range
+	InvalidSum1 *big.Int      // Part 1: exactly twice
+	InvalidSum2 *big.Int      // Part 2: at least twice
+	Rules       []Rule        // more invalid ID rules to evaluate
+	RuleSums    []*big.Int    // sum for each of Rules
+	Report      bool          // build Reports, which costs a few big.Ints per range
+	Reports     []RangeReport // what each merged range contributes, when Report is set
+	ListIDs     int           // invalid IDs to list per range and rule in Reports
+	Base        int
 }
 
 func NewGiftShop() *GiftShop {
-	return &GiftShop{}
+	return &GiftShop{Base: 10, InvalidSum1: new(big.Int), InvalidSum2: new(big.Int)}
 }
 
 // Parse reads product ID ranges from r.
-// Each range is formatted as "start-end" and separated by commas.
+// Each range is formatted as "start-end" in the shop's base and separated by commas.
+// Overlapping ranges are merged before summing so no ID is counted twice.
 func (g *GiftShop) Parse(r io.Reader) {
+	g.parseInput(r)
+	g.solve()
+}
+
+// parseInput reads the ranges and merges them.
+func (g *GiftShop) parseInput(r io.Reader) {
 	scanner := bufio.NewScanner(r)
 	var input strings.Builder
 	for scanner.Scan() {
@@ -48,118 +67,267 @@ func (g *GiftShop) Parse(r io.Reader) {
 			continue
 		}
 
-		start, err1 := strconv.ParseInt(bounds[0], 10, 64)
-		end, err2 := strconv.ParseInt(bounds[1], 10, 64)
-		if err1 != nil || err2 != nil {
+		start, ok1 := new(big.Int).SetString(bounds[0], g.Base)
+		end, ok2 := new(big.Int).SetString(bounds[1], g.Base)
+		if !ok1 || !ok2 || start.Sign() < 0 || start.Cmp(end) > 0 {
 			continue
 		}
 
-		g.Ranges = append(g.Ranges, [2]int64{start, end})
+		g.Ranges = append(g.Ranges, [2]*big.Int{start, end})
 	}
+	g.mergeRanges()
+}
 
-	// Consolidate invalid IDs for all ranges
-	g.InvalidSum1 = 0
-	g.InvalidSum2 = 0
-	for _, r := range g.Ranges {
-		ids1 := g.findInvalidIDsInRange(r[0], r[1], false)
-		ids2 := g.findInvalidIDsInRange(r[0], r[1], true)
+// solve evaluates the puzzle parts and extra rules over the merged ranges.
+func (g *GiftShop) solve() {
+	// Consolidate invalid IDs for all ranges, the puzzle parts first
+	rules := append([]Rule{exactlyTwice{}, atLeastTwice{}}, g.Rules...)
+	sums := make([]*big.Int, len(rules))
+	for i := range sums {
+		sums[i] = new(big.Int)
+	}
 
-		g.InvalidSum1 += sum(ids1)
-		g.InvalidSum2 += sum(ids2)
+	// Without reports, the puzzle parts of ranges that fit in uint64 are
+	// summed in a u128. The merged ranges don't overlap, so their IDs add up
+	// to less than 2^128 and the totals can't wrap.
+	var part1, part2 u128
+	g.Reports = nil
+	for _, r := range g.Merged {
+		first := 0
+		if !g.Report {
+			if lo, hi, ok := g.fitUint64(r[0], r[1]); ok {
+				_, s1 := exactlyTwice64(lo, hi, g.Base)
+				_, s2 := atLeastTwice64(lo, hi, g.Base)
+				part1, part2 = part1.add(s1), part2.add(s2)
+				first = 2
+			}
+		}
 
-		if g.Verbose {
-			fmt.Printf("  %d-%d: part1=%v part2=%v\n", r[0], r[1], ids1, ids2)
+		report := RangeReport{Start: r[0], End: r[1]}
+		if g.Report {
+			report.Rules = make([]RuleReport, len(rules))
+		}
+		for i := first; i < len(rules); i++ {
+			rr := g.evaluate(rules[i], r[0], r[1])
+			sums[i].Add(sums[i], rr.Sum)
+			if g.Report {
+				report.Rules[i] = rr
+			}
+		}
+		if g.Report {
+			g.Reports = append(g.Reports, report)
 		}
 	}
+	sums[0].Add(sums[0], part1.big())
+	sums[1].Add(sums[1], part2.big())
+	g.InvalidSum1, g.InvalidSum2, g.RuleSums = sums[0], sums[1], sums[2:]
 }
 
-func (g *GiftShop) String() string {
-	return fmt.Sprintf("ranges: %d, part1: %d, part2: %d", len(g.Ranges), g.InvalidSum1, g.InvalidSum2)
-}
+// mergeRanges sorts the ranges into Merged, joining any that overlap or
+// touch, and records the IDs that were covered more than once in Overlaps.
+func (g *GiftShop) mergeRanges() {
+	sorted := slices.Clone(g.Ranges)
+	slices.SortFunc(sorted, func(a, b [2]*big.Int) int { return a[0].Cmp(b[0]) })
+
+	g.Merged, g.Overlaps = nil, nil
+	one := big.NewInt(1)
+	for _, r := range sorted {
+		if len(g.Merged) == 0 {
+			g.Merged = append(g.Merged, [2]*big.Int{r[0], new(big.Int).Set(r[1])})
+			continue
+		}
 
-// findInvalidIDsInRange returns all invalid IDs within the given range.
-func (g *GiftShop) findInvalidIDsInRange(start, end int64, atLeastTwice bool) []int64 {
-	startLen := digitLength(start)
-	endLen := digitLength(end)
+		last := &g.Merged[len(g.Merged)-1]
+		if r[0].Cmp(last[1]) <= 0 {
+			// Overlaps come in order of their start, so a new one can
+			// only extend the previous one
+			overlap := [2]*big.Int{r[0], new(big.Int).Set(minBig(r[1], last[1]))}
+			if n := len(g.Overlaps); n > 0 && overlap[0].Cmp(g.Overlaps[n-1][1]) <= 0 {
+				g.Overlaps[n-1][1] = maxBig(g.Overlaps[n-1][1], overlap[1])
+			} else {
+				g.Overlaps = append(g.Overlaps, overlap)
+			}
+		}
 
-	if atLeastTwice {
-		return g.findAtLeastTwiceInRange(start, end, startLen, endLen)
+		if next := new(big.Int).Add(last[1], one); r[0].Cmp(next) <= 0 {
+			last[1] = maxBig(last[1], r[1])
+		} else {
+			g.Merged = append(g.Merged, [2]*big.Int{r[0], new(big.Int).Set(r[1])})
+		}
 	}
-	return g.findExactlyTwiceInRange(start, end, startLen, endLen)
 }
 
-// findAtLeastTwiceInRange finds IDs with a pattern repeated 2 or more times.
-func (g *GiftShop) findAtLeastTwiceInRange(start, end int64, startLen, endLen int) []int64 {
-	var ids []int64
-	seen := make(map[int64]bool)
+func (g *GiftShop) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "ranges: %d, part1: %s, part2: %s", len(g.Ranges), g.Format(g.InvalidSum1), g.Format(g.InvalidSum2))
+	for i, rule := range g.Rules {
+		fmt.Fprintf(&sb, ", %s: %s", rule.Name(), g.Format(g.RuleSums[i]))
+	}
+	return sb.String()
+}
 
-	for numDigits := startLen; numDigits <= endLen; numDigits++ {
-		g.collectAtLeastTwiceIDs(numDigits, start, end, seen, &ids)
+// Format writes n in the shop's base, followed by its decimal value when
+// the base isn't 10.
+func (g *GiftShop) Format(n *big.Int) string {
+	if g.Base == 10 {
+		return n.String()
 	}
-	return ids
+	return fmt.Sprintf("%s (%s)", g.formatID(n), n)
 }
 
-// collectAtLeastTwiceIDs collects IDs of a specific digit length with repeated patterns.
-func (g *GiftShop) collectAtLeastTwiceIDs(numDigits int, start, end int64, seen map[int64]bool, ids *[]int64) {
-	for patternLen := 1; patternLen <= numDigits/2; patternLen++ {
-		if numDigits%patternLen != 0 {
-			continue
-		}
+// formatID writes id in the shop's base.
+func (g *GiftShop) formatID(id *big.Int) string {
+	return id.Text(g.Base)
+}
 
-		// Calculate the number of repetitions for the pattern
-		repetitions := numDigits / patternLen
-		if repetitions < 2 {
-			continue
+// exactlyTwice counts and sums the IDs in the range made of a pattern repeated exactly twice.
+func (g *GiftShop) exactlyTwice(start, end *big.Int) (count, sum *big.Int) {
+	if lo, hi, ok := g.fitUint64(start, end); ok {
+		c, s := exactlyTwice64(lo, hi, g.Base)
+		return new(big.Int).SetUint64(c), s.big()
+	}
+
+	count, sum = new(big.Int), new(big.Int)
+	for numDigits := digitLength(start, g.Base); numDigits <= digitLength(end, g.Base); numDigits++ {
+		if numDigits%2 == 0 {
+			c, s := withPeriod(start, end, numDigits, numDigits/2, g.Base)
+			count.Add(count, c)
+			sum.Add(sum, s)
 		}
+	}
+	return count, sum
+}
+
+// atLeastTwice counts and sums the IDs in the range made of a pattern repeated two or more times.
+//
+// An ID with numDigits digits is invalid when it repeats with some period p
+// that properly divides numDigits. Every such period divides numDigits/q for
+// a prime q, so the invalid IDs are the union of the sets with those periods.
+// Two of these sets intersect in the set for their gcd period, so
+// inclusion-exclusion over the primes reduces to a Möbius sum:
+//
+//	sum = -Σ μ(d)·withPeriod(numDigits/d)  for d | numDigits, d > 1
+func (g *GiftShop) atLeastTwice(start, end *big.Int) (count, sum *big.Int) {
+	if lo, hi, ok := g.fitUint64(start, end); ok {
+		c, s := atLeastTwice64(lo, hi, g.Base)
+		return new(big.Int).SetUint64(c), s.big()
+	}
 
-		// Generate all patterns of the given length
-		minPattern, maxPattern := patternBounds(patternLen)
-		for pattern := minPattern; pattern <= maxPattern; pattern++ {
-			invalidID := buildRepeatedID(pattern, patternLen, repetitions)
-			if invalidID >= start && invalidID <= end && !seen[invalidID] {
-				*ids = append(*ids, invalidID)
-				seen[invalidID] = true
+	count, sum = new(big.Int), new(big.Int)
+	for numDigits := digitLength(start, g.Base); numDigits <= digitLength(end, g.Base); numDigits++ {
+		for d := 2; d <= numDigits; d++ {
+			if numDigits%d != 0 {
+				continue
+			}
+			if mu := big.NewInt(int64(mobius(d))); mu.Sign() != 0 {
+				c, s := withPeriod(start, end, numDigits, numDigits/d, g.Base)
+				count.Sub(count, c.Mul(c, mu))
+				sum.Sub(sum, s.Mul(s, mu))
 			}
 		}
 	}
+	return count, sum
 }
 
-// findExactlyTwiceInRange finds IDs with a pattern repeated exactly twice.
-func (g *GiftShop) findExactlyTwiceInRange(start, end int64, startLen, endLen int) []int64 {
-	var ids []int64
+// withPeriod counts and sums the numDigits-digit IDs within the range whose
+// digits repeat every patternLen digits. Those IDs are pattern·m for a fixed
+// multiplier m (e.g. 1001001 for 3 repeats of 3 decimal digits), so the patterns in
+// range form a contiguous block and the sum is an arithmetic series.
+func withPeriod(start, end *big.Int, numDigits, patternLen, base int) (count, sum *big.Int) {
+	first, last, m := patternRange(start, end, numDigits, patternLen, base)
+	if first.Cmp(last) > 0 {
+		return new(big.Int), new(big.Int)
+	}
 
-	for numDigits := startLen; numDigits <= endLen; numDigits++ {
-		if numDigits%2 != 0 {
-			continue
-		}
+	count = new(big.Int).Sub(last, first)
+	count.Add(count, big.NewInt(1))
+	sum = seriesSum(first, last)
+	return count, sum.Mul(sum, m)
+}
+
+// patternRange returns the first and last patterns of patternLen digits
+// that repeat into a numDigits-digit ID within the range, along with the
+// multiplier m that repeats them. first > last when there are none.
+func patternRange(start, end *big.Int, numDigits, patternLen, base int) (first, last, m *big.Int) {
+	lo, hi := patternBounds(numDigits, base)
+	lo, hi = maxBig(lo, start), minBig(hi, end)
+
+	m = buildRepeatedID(big.NewInt(1), patternLen, numDigits/patternLen, base)
+	minPattern, maxPattern := patternBounds(patternLen, base)
+	first = maxBig(minPattern, ceilDiv(lo, m))
+	last = minBig(maxPattern, new(big.Int).Quo(hi, m))
+	return first, last, m
+}
+
+// findInvalidIDsInRange returns the smallest invalid IDs within the given
+// range in order, at most limit of them or all of them if limit is zero.
+// Each ID is generated once from its shortest repeating pattern.
+func (g *GiftShop) findInvalidIDsInRange(start, end *big.Int, atLeastTwice bool, limit int) []*big.Int {
+	var ids []*big.Int
 
-		halfLen := numDigits / 2
-		minHalf, maxHalf := patternBounds(halfLen)
+	for numDigits := digitLength(start, g.Base); numDigits <= digitLength(end, g.Base); numDigits++ {
+		// Longer IDs are larger, so each length only needs sorting on its own
+		lengthStart := len(ids)
+		for patternLen := 1; patternLen <= numDigits/2; patternLen++ {
+			if numDigits%patternLen != 0 || (!atLeastTwice && patternLen*2 != numDigits) {
+				continue
+			}
 
-		// Generate all halves and form the invalid ID by repeating the half
-		for half := minHalf; half <= maxHalf; half++ {
-			invalidID := buildRepeatedID(half, halfLen, 2)
-			if invalidID >= start && invalidID <= end {
-				ids = append(ids, invalidID)
+			// Patterns give increasing IDs, so at most limit are needed from each
+			found := 0
+			first, last, m := patternRange(start, end, numDigits, patternLen, g.Base)
+			for pattern := first; pattern.Cmp(last) <= 0 && (limit == 0 || found < limit); pattern = new(big.Int).Add(pattern, big.NewInt(1)) {
+				// Exactly twice counts "1111" as "11" twice, so only
+				// skip non-primitive patterns when deduplicating
+				if atLeastTwice && !isPrimitivePattern(pattern, g.Base) {
+					continue
+				}
+				ids = append(ids, new(big.Int).Mul(pattern, m))
+				found++
 			}
 		}
+		slices.SortFunc(ids[lengthStart:], (*big.Int).Cmp)
+
+		if limit > 0 && len(ids) >= limit {
+			return ids[:limit]
+		}
 	}
+
 	return ids
 }
 
 func main() {
 	var inputFile string
-	var verbose bool
+	var reportRun bool
+	var ranges string
+	var listIDs int
+	var palindrome bool
+	var run, digitSum int
+	var match string
+	var query Repetition
+	var base int
 
 	flag.StringVar(&inputFile, "input", "day02/input.txt", "input file path")
 	flag.StringVar(&inputFile, "i", "day02/input.txt", "input file path (shorthand)")
-	flag.BoolVar(&verbose, "verbose", false, "print invalid IDs found")
-	flag.BoolVar(&verbose, "v", false, "print invalid IDs found (shorthand)")
+	flag.BoolVar(&reportRun, "report", false, "print a JSON run record for the aoc report")
+	flag.StringVar(&ranges, "ranges", "", "print what each range contributes as a table, csv or json")
+	flag.IntVar(&listIDs, "ids", 0, "with -ranges, list up to this many invalid IDs per range and rule")
+	flag.IntVar(&base, "base", 10, "base the ranges are written in, from 2 to 36")
+	flag.IntVar(&query.Exactly, "exact", 0, "also count and sum IDs whose unit repeats exactly this many times")
+	flag.IntVar(&query.AtLeast, "min", 0, "also count and sum IDs whose unit repeats at least this many times")
+	flag.IntVar(&query.UnitLen, "unit", 0, "also count and sum repeated IDs whose unit has this many digits")
+	flag.BoolVar(&palindrome, "palindrome", false, "also sum IDs that read the same backwards")
+	flag.IntVar(&run, "run", 0, "also sum IDs with this many of the same digit in a row")
+	flag.IntVar(&digitSum, "digitsum", 0, "also sum IDs whose digits add up to a multiple of this")
+	flag.StringVar(&match, "match", "", "also sum IDs whose digits match this regular expression")
 	flag.Parse()
 
 	if inputFile == "" {
 		log.Fatal("no input file specified")
 	}
+	if base < 2 || base > 36 {
+		log.Fatal("base must be between 2 and 36")
+	}
 
 	f, err := os.Open(filepath.Clean(inputFile))
 	if err != nil {
@@ -168,7 +336,65 @@ func main() {
 	defer f.Close()
 
 	shop := NewGiftShop()
-	shop.Verbose = verbose
+	shop.Base = base
+	shop.Report = ranges != ""
+	shop.ListIDs = listIDs
+	if palindrome {
+		shop.Rules = append(shop.Rules, Palindrome{})
+	}
+	if run > 0 {
+		shop.Rules = append(shop.Rules, Run{N: run})
+	}
+	if digitSum > 0 {
+		shop.Rules = append(shop.Rules, DigitSum{K: digitSum})
+	}
+	if match != "" {
+		rule, err := NewMatch(match)
+		if err != nil {
+			log.Fatal(err)
+		}
+		shop.Rules = append(shop.Rules, rule)
+	}
+	if reportRun {
+		run, err := report.MeasureSteps(2, f, shop.parseInput, shop.solve)
+		if err != nil {
+			log.Fatal(err)
+		}
+		run.Answers = []string{shop.InvalidSum1.String(), shop.InvalidSum2.String()}
+		if err := run.Write(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	shop.Parse(f)
+	for _, o := range shop.Overlaps {
+		fmt.Fprintf(os.Stderr, "warning: %s-%s is covered by more than one range\n", shop.formatID(o[0]), shop.formatID(o[1]))
+	}
+
+	switch ranges {
+	case "":
+	case "table":
+		err = shop.WriteReportTable(os.Stdout)
+	case "csv":
+		err = shop.WriteReportCSV(os.Stdout)
+	case "json":
+		err = shop.WriteReportJSON(os.Stdout)
+	default:
+		err = fmt.Errorf("unknown ranges format %q", ranges)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Keep CSV and JSON output machine readable
+	if ranges == "csv" || ranges == "json" {
+		return
+	}
 	fmt.Println(shop)
+
+	if query != (Repetition{}) {
+		count, sum := shop.Query(query)
+		fmt.Printf("query: count %s, sum %s\n", count, shop.Format(sum))
+	}
 }
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"text/tabwriter"
+)
+
+// RangeReport records what one merged range contributes to each rule.
+type RangeReport struct {
+	Start *big.Int     `json:"start"`
+	End   *big.Int     `json:"end"`
+	Rules []RuleReport `json:"rules"`
+}
+
+// RuleReport counts and sums the IDs in a range that break one rule.
+type RuleReport struct {
+	Rule  string     `json:"rule"`
+	Count *big.Int   `json:"count"`
+	Sum   *big.Int   `json:"sum"`
+	IDs   []*big.Int `json:"ids,omitempty"` // smallest invalid IDs, up to GiftShop.ListIDs
+}
+
+// WriteReportTable prints the range reports as an aligned table, with
+// numbers in the shop's base. Listed IDs end in "..." when there are more.
+func (g *GiftShop) WriteReportTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "RANGE\tRULE\tCOUNT\tSUM\tIDS")
+	for _, r := range g.Reports {
+		for _, rule := range r.Rules {
+			fmt.Fprintf(tw, "%s-%s\t%s\t%s\t%s\t%s\n", g.formatID(r.Start), g.formatID(r.End),
+				rule.Rule, rule.Count, g.formatID(rule.Sum), g.listIDs(rule))
+		}
+	}
+	return tw.Flush()
+}
+
+// WriteReportCSV prints the range reports as CSV with a header row, one row
+// per range and rule, with numbers in the shop's base.
+func (g *GiftShop) WriteReportCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"start", "end", "rule", "count", "sum", "ids"})
+	for _, r := range g.Reports {
+		for _, rule := range r.Rules {
+			cw.Write([]string{g.formatID(r.Start), g.formatID(r.End),
+				rule.Rule, rule.Count.String(), g.formatID(rule.Sum), g.listIDs(rule)})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteReportJSON prints the range reports as a JSON array, with numbers
+// in decimal.
+func (g *GiftShop) WriteReportJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(g.Reports)
+}
+
+// listIDs joins the listed IDs of a rule with spaces, ending in "..." when
+// the rule has more than were listed.
+func (g *GiftShop) listIDs(rule RuleReport) string {
+	ids := make([]string, len(rule.IDs))
+	for i, id := range rule.IDs {
+		ids[i] = g.formatID(id)
+	}
+	if len(ids) > 0 && rule.Count.Cmp(big.NewInt(int64(len(ids)))) > 0 {
+		ids = append(ids, "...")
+	}
+	return strings.Join(ids, " ")
+}
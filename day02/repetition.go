@@ -0,0 +1,122 @@
+package main
+
+import "math/big"
+
+// Repetition selects IDs by how their smallest repeating unit repeats. For
+// example 123123123 has the unit 123 repeated 3 times, and 1111 has the unit
+// 1 repeated 4 times. Zero fields are ignored, and a zero Repetition matches
+// every ID repeated at least twice, the same IDs as part 2.
+//
+// Note that Exactly counts repeats of the smallest unit, so Exactly: 2 does
+// not match 1111 even though part 1 counts it as 11 twice.
+type Repetition struct {
+	Exactly int // unit repeated exactly this many times
+	AtLeast int // unit repeated at least this many times
+	UnitLen int // unit is this many digits long
+	Base    int // base the digits are written in, 10 if zero
+}
+
+// Classify returns the smallest repeating unit of id and how many times it
+// repeats. An ID that doesn't repeat is its own unit, repeated once.
+func Classify(id *big.Int) (unit *big.Int, repetitions int) {
+	return ClassifyBase(id, 10)
+}
+
+// ClassifyBase is Classify with the digits of id written in base.
+func ClassifyBase(id *big.Int, base int) (unit *big.Int, repetitions int) {
+	digits := id.Text(base)
+	unitLen := smallestPeriod(digits)
+	unit, _ = new(big.Int).SetString(digits[:unitLen], base)
+	return unit, len(digits) / unitLen
+}
+
+// Matches reports whether id is selected by the query.
+func (q Repetition) Matches(id *big.Int) bool {
+	unit, reps := ClassifyBase(id, q.base())
+	return q.matches(digitLength(unit, q.base()), reps)
+}
+
+func (q Repetition) base() int {
+	if q.Base == 0 {
+		return 10
+	}
+	return q.Base
+}
+
+// matches reports whether a smallest unit of unitLen digits repeated reps
+// times is selected by the query.
+func (q Repetition) matches(unitLen, reps int) bool {
+	if q.Exactly == 0 && reps < max(q.AtLeast, 2) {
+		return false
+	}
+	if q.Exactly != 0 && reps != q.Exactly {
+		return false
+	}
+	return reps >= q.AtLeast && (q.UnitLen == 0 || unitLen == q.UnitLen)
+}
+
+// Count returns how many IDs in the range are selected by the query.
+func (q Repetition) Count(start, end *big.Int) *big.Int {
+	count, _ := q.evaluate(start, end)
+	return count
+}
+
+// Sum returns the sum of the IDs in the range selected by the query.
+func (q Repetition) Sum(start, end *big.Int) *big.Int {
+	_, sum := q.evaluate(start, end)
+	return sum
+}
+
+// evaluate counts and sums the selected IDs one digit length at a time,
+// adding up the IDs with each matching smallest unit length.
+func (q Repetition) evaluate(start, end *big.Int) (count, sum *big.Int) {
+	count, sum = new(big.Int), new(big.Int)
+	base := q.base()
+	for numDigits := digitLength(start, base); numDigits <= digitLength(end, base); numDigits++ {
+		for unitLen := 1; unitLen <= numDigits; unitLen++ {
+			if numDigits%unitLen != 0 || !q.matches(unitLen, numDigits/unitLen) {
+				continue
+			}
+			c, s := withSmallestUnit(start, end, numDigits, unitLen, base)
+			count.Add(count, c)
+			sum.Add(sum, s)
+		}
+	}
+	return count, sum
+}
+
+// withSmallestUnit counts and sums the numDigits-digit IDs in the range whose
+// smallest repeating unit is unitLen digits long. IDs that repeat every
+// unitLen digits may also repeat with a shorter period dividing unitLen, so
+// those are removed by Möbius inversion over the divisors of unitLen.
+func withSmallestUnit(start, end *big.Int, numDigits, unitLen, base int) (count, sum *big.Int) {
+	count, sum = new(big.Int), new(big.Int)
+	for d := 1; d <= unitLen; d++ {
+		if unitLen%d != 0 {
+			continue
+		}
+		mu := big.NewInt(int64(mobius(d)))
+		if mu.Sign() == 0 {
+			continue
+		}
+		c, s := withPeriod(start, end, numDigits, unitLen/d, base)
+		count.Add(count, c.Mul(c, mu))
+		sum.Add(sum, s.Mul(s, mu))
+	}
+	return count, sum
+}
+
+// Query counts and sums the IDs selected by q across all ranges, with
+// overlapping ranges merged. A query without a base uses the shop's.
+func (g *GiftShop) Query(q Repetition) (count, sum *big.Int) {
+	if q.Base == 0 {
+		q.Base = g.Base
+	}
+	count, sum = new(big.Int), new(big.Int)
+	for _, r := range g.Merged {
+		c, s := q.evaluate(r[0], r[1])
+		count.Add(count, c)
+		sum.Add(sum, s)
+	}
+	return count, sum
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// Rule is a family of invalid IDs. Rules added to GiftShop.Rules are
+// evaluated over every merged range alongside the two puzzle parts, and get
+// their own sums. Rules check one ID at a time, so they suit ranges of up to
+// a few million IDs.
+type Rule interface {
+	// Name labels the rule in reports and output.
+	Name() string
+	// Invalid reports whether an ID, written as digits in the shop's base,
+	// breaks the rule.
+	Invalid(digits string) bool
+}
+
+// generator is implemented by rules that can count, sum and list their
+// invalid IDs in a range without checking each ID.
+type generator interface {
+	generate(g *GiftShop, start, end *big.Int) RuleReport
+}
+
+// exactlyTwice is the part 1 rule, a pattern repeated exactly twice.
+type exactlyTwice struct{}
+
+func (exactlyTwice) Name() string { return "part1" }
+
+func (exactlyTwice) Invalid(digits string) bool {
+	half := len(digits) / 2
+	return len(digits)%2 == 0 && digits[:half] == digits[half:]
+}
+
+func (exactlyTwice) generate(g *GiftShop, start, end *big.Int) RuleReport {
+	count, sum := g.exactlyTwice(start, end)
+	r := RuleReport{Rule: "part1", Count: count, Sum: sum}
+	if g.ListIDs > 0 {
+		r.IDs = g.findInvalidIDsInRange(start, end, false, g.ListIDs)
+	}
+	return r
+}
+
+// atLeastTwice is the part 2 rule, a pattern repeated two or more times.
+type atLeastTwice struct{}
+
+func (atLeastTwice) Name() string { return "part2" }
+
+func (atLeastTwice) Invalid(digits string) bool {
+	return smallestPeriod(digits) < len(digits)
+}
+
+func (atLeastTwice) generate(g *GiftShop, start, end *big.Int) RuleReport {
+	count, sum := g.atLeastTwice(start, end)
+	r := RuleReport{Rule: "part2", Count: count, Sum: sum}
+	if g.ListIDs > 0 {
+		r.IDs = g.findInvalidIDsInRange(start, end, true, g.ListIDs)
+	}
+	return r
+}
+
+// Palindrome matches IDs that read the same backwards, e.g. 12321.
+type Palindrome struct{}
+
+func (Palindrome) Name() string { return "palindrome" }
+
+func (Palindrome) Invalid(digits string) bool {
+	for i, j := 0, len(digits)-1; i < j; i, j = i+1, j-1 {
+		if digits[i] != digits[j] {
+			return false
+		}
+	}
+	return true
+}
+
+// Run matches IDs with N or more of the same digit in a row, e.g. 12333
+// for N = 3.
+type Run struct {
+	N int
+}
+
+func (r Run) Name() string { return fmt.Sprintf("run%d", r.N) }
+
+func (r Run) Invalid(digits string) bool {
+	length := 0
+	for i := range len(digits) {
+		if i > 0 && digits[i] == digits[i-1] {
+			length++
+		} else {
+			length = 1
+		}
+		if length >= r.N {
+			return true
+		}
+	}
+	return false
+}
+
+// DigitSum matches IDs whose digits add up to a multiple of K, which must be
+// positive, e.g. 147 for K = 3. Digits above 9 count as their value, so "a"
+// adds 10.
+type DigitSum struct {
+	K int
+}
+
+func (d DigitSum) Name() string { return fmt.Sprintf("digitsum%d", d.K) }
+
+func (d DigitSum) Invalid(digits string) bool {
+	total := 0
+	for _, c := range digits {
+		total += strings.IndexRune("0123456789abcdefghijklmnopqrstuvwxyz", c)
+	}
+	return total%d.K == 0
+}
+
+// Match matches IDs whose digits match a regular expression anywhere, so
+// anchor it to match the whole ID.
+type Match struct {
+	re *regexp.Regexp
+}
+
+// NewMatch compiles expr into a Match rule.
+func NewMatch(expr string) (Match, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return Match{}, err
+	}
+	return Match{re}, nil
+}
+
+func (m Match) Name() string { return "match " + m.re.String() }
+
+func (m Match) Invalid(digits string) bool {
+	return m.re.MatchString(digits)
+}
+
+// evaluate counts, sums and lists the IDs in the range that break rule,
+// through its generator when it has one and otherwise one ID at a time.
+func (g *GiftShop) evaluate(rule Rule, start, end *big.Int) RuleReport {
+	if gen, ok := rule.(generator); ok {
+		return gen.generate(g, start, end)
+	}
+
+	r := RuleReport{Rule: rule.Name(), Count: new(big.Int), Sum: new(big.Int)}
+	one := big.NewInt(1)
+	for id := new(big.Int).Set(start); id.Cmp(end) <= 0; id.Add(id, one) {
+		if !rule.Invalid(id.Text(g.Base)) {
+			continue
+		}
+		r.Count.Add(r.Count, one)
+		r.Sum.Add(r.Sum, id)
+		if len(r.IDs) < g.ListIDs {
+			r.IDs = append(r.IDs, new(big.Int).Set(id))
+		}
+	}
+	return r
+}
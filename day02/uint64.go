@@ -0,0 +1,144 @@
+package main
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// The puzzle parts count and sum ranges that fit in uint64 without big.Int,
+// which would otherwise allocate for every intermediate value. Patterns are
+// at most half an ID's digits, so every value but the sums fits in uint64.
+
+// u128 is an unsigned 128-bit integer that wraps on overflow like uint64.
+// The Möbius sums may pass through negative values, but their totals are
+// never negative and always fit.
+type u128 struct {
+	hi, lo uint64
+}
+
+func (a u128) add(b u128) u128 {
+	lo, carry := bits.Add64(a.lo, b.lo, 0)
+	hi, _ := bits.Add64(a.hi, b.hi, carry)
+	return u128{hi, lo}
+}
+
+func (a u128) sub(b u128) u128 {
+	lo, borrow := bits.Sub64(a.lo, b.lo, 0)
+	hi, _ := bits.Sub64(a.hi, b.hi, borrow)
+	return u128{hi, lo}
+}
+
+// mul returns a·b, wrapping past 128 bits.
+func (a u128) mul(b uint64) u128 {
+	hi, lo := bits.Mul64(a.lo, b)
+	return u128{hi + a.hi*b, lo}
+}
+
+// big returns a as a big.Int.
+func (a u128) big() *big.Int {
+	n := new(big.Int).SetUint64(a.hi)
+	if a.hi == 0 {
+		return n.SetUint64(a.lo)
+	}
+	return n.Lsh(n, 64).Or(n, new(big.Int).SetUint64(a.lo))
+}
+
+// pow64 returns base^n, and false if it overflows uint64.
+func pow64(base, n int) (uint64, bool) {
+	result := uint64(1)
+	for range n {
+		hi, lo := bits.Mul64(result, uint64(base))
+		if hi != 0 {
+			return 0, false
+		}
+		result = lo
+	}
+	return result, true
+}
+
+// digitLength64 returns the number of digits in n written in base.
+func digitLength64(n uint64, base int) int {
+	length := 1
+	for n >= uint64(base) {
+		n /= uint64(base)
+		length++
+	}
+	return length
+}
+
+// fitUint64 returns the range as uint64, and false if its IDs can have more
+// digits than uint64 holds in the shop's base.
+func (g *GiftShop) fitUint64(start, end *big.Int) (lo, hi uint64, ok bool) {
+	if !end.IsUint64() {
+		return 0, 0, false
+	}
+	lo, hi = start.Uint64(), end.Uint64()
+	if _, ok := pow64(g.Base, digitLength64(hi, g.Base)); !ok {
+		return 0, 0, false
+	}
+	return lo, hi, true
+}
+
+// exactlyTwice64 is exactlyTwice for a range that fits in uint64.
+func exactlyTwice64(start, end uint64, base int) (count uint64, sum u128) {
+	for numDigits := digitLength64(start, base); numDigits <= digitLength64(end, base); numDigits++ {
+		if numDigits%2 == 0 {
+			c, s := withPeriod64(start, end, numDigits, numDigits/2, base)
+			count += c
+			sum = sum.add(s)
+		}
+	}
+	return count, sum
+}
+
+// atLeastTwice64 is atLeastTwice for a range that fits in uint64.
+func atLeastTwice64(start, end uint64, base int) (count uint64, sum u128) {
+	for numDigits := digitLength64(start, base); numDigits <= digitLength64(end, base); numDigits++ {
+		for d := 2; d <= numDigits; d++ {
+			if numDigits%d != 0 {
+				continue
+			}
+			c, s := withPeriod64(start, end, numDigits, numDigits/d, base)
+			switch mobius(d) {
+			case 1:
+				count -= c
+				sum = sum.sub(s)
+			case -1:
+				count += c
+				sum = sum.add(s)
+			}
+		}
+	}
+	return count, sum
+}
+
+// withPeriod64 is withPeriod for a range that fits in uint64, with
+// patternLen at most half of numDigits.
+func withPeriod64(start, end uint64, numDigits, patternLen, base int) (count uint64, sum u128) {
+	lo, _ := pow64(base, numDigits-1)
+	hi, _ := pow64(base, numDigits)
+	lo, hi = max(lo, start), min(hi-1, end)
+
+	shift, _ := pow64(base, patternLen)
+	m := uint64(0)
+	for range numDigits / patternLen {
+		m = m*shift + 1
+	}
+
+	minPattern, _ := pow64(base, patternLen-1)
+	first := lo / m
+	if lo%m != 0 {
+		first++
+	}
+	first = max(minPattern, first)
+	last := min(shift-1, hi/m)
+	if first > last {
+		return 0, u128{}
+	}
+
+	// Patterns have at most half the digits, so first+last can't overflow
+	count = last - first + 1
+	series := u128{lo: first + last}.mul(count)
+	series = u128{series.hi >> 1, series.lo>>1 | series.hi<<63}
+	return count, series.mul(m)
+}
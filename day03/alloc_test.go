@@ -0,0 +1,42 @@
+package main_test
+
+import (
+	"io"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	main "github.com/lcox74/aoc25/day03"
+	"github.com/lcox74/aoc25/internal/report"
+)
+
+// budget for parsing and solving generatedInput, about 25% over measured.
+var budget = report.Budget{Allocs: 260, Bytes: 34_000}
+
+// generatedInput returns 200 banks of 100 random battery digits.
+func generatedInput() string {
+	rng := rand.New(rand.NewPCG(29, 3))
+
+	var sb strings.Builder
+	for range 200 {
+		for range 100 {
+			sb.WriteByte(byte('1' + rng.IntN(9)))
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+func TestAllocBudget(t *testing.T) {
+	budget.Check(t, generatedInput(), func(r io.Reader) {
+		main.NewBatteryBank().Parse(r)
+	})
+}
+
+func BenchmarkParse(b *testing.B) {
+	input := generatedInput()
+	b.ReportAllocs()
+	for b.Loop() {
+		main.NewBatteryBank().Parse(strings.NewReader(input))
+	}
+}
@@ -19,8 +19,40 @@ func TestExample(t *testing.T) {
 	bank.Parse(strings.NewReader(exampleInput))
 
 	// Part 1: 98 + 89 + 78 + 92 = 357
-	require.Equal(t, int64(357), bank.TotalJoltage2Bat)
+	require.Equal(t, int64(357), bank.Total(2).Int64())
 
 	// Part 2: 987654321111 + 811111111119 + 434234234278 + 888911112111 = 3121910778619
-	require.Equal(t, int64(3121910778619), bank.TotalJoltage12Bat)
+	require.Equal(t, int64(3121910778619), bank.Total(12).Int64())
+}
+
+func TestSizes(t *testing.T) {
+	bank := main.NewBatteryBank()
+	bank.Sizes = []int{15, 16, 19}
+	bank.Parse(strings.NewReader(exampleInput + "\n98765432119876543211"))
+
+	// Every battery in the 15 digit banks, which are too short for more
+	require.Equal(t, "3838841454111830", bank.Total(15).String())
+	require.Equal(t, "9876549876543211", bank.Total(16).String())
+	require.Equal(t, "9876543219876543211", bank.Total(19).String())
+	require.Nil(t, bank.Total(2))
+}
+
+func TestExplain(t *testing.T) {
+	bank := main.NewBatteryBank()
+	bank.Explain = true
+	bank.Parse(strings.NewReader(exampleInput))
+
+	require.Len(t, bank.Explanations, 4)
+	e := bank.Explanations[3]
+	require.Equal(t, 4, e.Line)
+	require.Equal(t, main.Selection{K: 2, Indices: []int{6, 11}, Digits: "92"}, e.Selections[0])
+	require.Equal(t, "888911112111", e.Selections[1].Digits)
+
+	var text strings.Builder
+	require.NoError(t, bank.WriteExplainText(&text, false))
+	require.Contains(t, text.String(), "line 4: 818181911112111\n    2: 818181[9]1111[2]111 = 92\n")
+
+	var colored strings.Builder
+	require.NoError(t, bank.WriteExplainText(&colored, true))
+	require.Contains(t, colored.String(), "818181\x1b[1;32m9\x1b[0m1111\x1b[1;32m2\x1b[0m111 = 92")
 }
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Explanation records which batteries were chosen from one bank.
+type Explanation struct {
+	Line       int         `json:"line"` // input line number, starting at 1
+	Bank       string      `json:"bank"`
+	Selections []Selection `json:"selections"` // one for each of BatteryBank.Sizes
+}
+
+// Selection is the batteries chosen for one selection size.
+type Selection struct {
+	K       int    `json:"k"`
+	Indices []int  `json:"indices"` // positions in the bank, in reading order
+	Digits  string `json:"digits"`  // the joltage they form
+}
+
+// Selections chooses batteries from bank for each of the sizes. Sizes larger
+// than the bank, or that no selection can satisfy, choose nothing.
+func (b *BatteryBank) Selections(bank string) []Selection {
+	selections := make([]Selection, len(b.Sizes))
+	for i, k := range b.Sizes {
+		selections[i].K = k
+		if len(bank) < k {
+			continue
+		}
+
+		indices := b.Select(nil, bank, k)
+		if len(indices) < k {
+			continue
+		}
+		digits := make([]byte, len(indices))
+		for j, idx := range indices {
+			digits[j] = bank[idx]
+		}
+		selections[i].Indices = indices
+		selections[i].Digits = string(digits)
+	}
+	return selections
+}
+
+// WriteExplainText prints each bank with its chosen batteries marked, in
+// bold green with ansi or in brackets otherwise.
+func (b *BatteryBank) WriteExplainText(w io.Writer, ansi bool) error {
+	bw := bufio.NewWriter(w)
+	for _, e := range b.Explanations {
+		fmt.Fprintf(bw, "line %d: %s\n", e.Line, e.Bank)
+		for _, s := range e.Selections {
+			fmt.Fprintf(bw, "  %3d: %s = %s\n", s.K, highlight(e.Bank, s.Indices, ansi), s.Digits)
+		}
+	}
+	return bw.Flush()
+}
+
+// WriteExplainJSON prints the explanations as a JSON array.
+func (b *BatteryBank) WriteExplainJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(b.Explanations)
+}
+
+// highlight marks the batteries at the indices in bank.
+func highlight(bank string, indices []int, ansi bool) string {
+	var sb strings.Builder
+	for i := range len(bank) {
+		if !slices.Contains(indices, i) {
+			sb.WriteByte(bank[i])
+		} else if ansi {
+			sb.WriteString("\x1b[1;32m" + bank[i:i+1] + "\x1b[0m")
+		} else {
+			sb.WriteString("[" + bank[i:i+1] + "]")
+		}
+	}
+	return sb.String()
+}
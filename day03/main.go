@@ -6,82 +6,159 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"math/big"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/lcox74/aoc25/internal/report"
 )
 
 // BatteryBank finds the maximum joltage from each bank of batteries.
 // Part 1: Select exactly 2 batteries to form a two-digit number.
 // Part 2: Select exactly 12 batteries to form a twelve-digit number.
+// Any other selection sizes can be totalled alongside them.
 type BatteryBank struct {
-	TotalJoltage2Bat  int64
-	TotalJoltage12Bat int64
+	Sizes  []int      // batteries to select from each bank
+	Totals []*big.Int // total joltage for each of Sizes
+	Select Selector
+
+	Explain      bool          // record the batteries chosen from each bank
+	Explanations []Explanation // one for each bank, when Explain is set
+
+	selected []int   // reused by findMaxJoltageN
+	joltage  big.Int // reused by findMaxJoltageN
+	digits   []byte  // reused by findMaxJoltageN
 }
 
 func NewBatteryBank() *BatteryBank {
-	return &BatteryBank{}
+	return &BatteryBank{Sizes: []int{2, 12}, Select: SelectStack}
 }
 
 // Parse reads battery banks from r, one per line.
 // For each bank, finds the maximum joltage by selecting batteries.
 func (b *BatteryBank) Parse(r io.Reader) {
+	b.Totals = make([]*big.Int, len(b.Sizes))
+	for i := range b.Totals {
+		b.Totals[i] = new(big.Int)
+	}
+
 	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxBankLen)
+	lineNum := 0
 	for scanner.Scan() {
+		lineNum++
 		line := scanner.Text()
 		if len(line) < 2 {
 			continue
 		}
 
-		b.TotalJoltage2Bat += b.findMaxJoltageN(line, 2)   // Part 1: select 2 batteries
-		b.TotalJoltage12Bat += b.findMaxJoltageN(line, 12) // Part 2: select 12 batteries
+		if b.Explain {
+			b.Explanations = append(b.Explanations, Explanation{lineNum, line, b.Selections(line)})
+		}
+
+		for i, k := range b.Sizes {
+			b.Totals[i].Add(b.Totals[i], b.findMaxJoltageN(line, k))
+		}
+	}
+}
+
+// maxBankLen is the longest bank Parse reads.
+const maxBankLen = 16 << 20
+
+// Total returns the total joltage selecting k batteries from each bank, or
+// nil if k isn't one of the sizes.
+func (b *BatteryBank) Total(k int) *big.Int {
+	if i := slices.Index(b.Sizes, k); i >= 0 && i < len(b.Totals) {
+		return b.Totals[i]
 	}
+	return nil
 }
 
 func (b *BatteryBank) String() string {
-	return fmt.Sprintf(
-		"Total Joltage: \n\tSmall Bat: %d jolts\n\tBig Bat: %d jolts",
-		b.TotalJoltage2Bat,
-		b.TotalJoltage12Bat,
-	)
+	var sb strings.Builder
+	sb.WriteString("Total Joltage: ")
+	for i, k := range b.Sizes {
+		fmt.Fprintf(&sb, "\n\t%s: %s jolts", sizeLabel(k), b.Totals[i])
+	}
+	return sb.String()
+}
+
+// sizeLabel names a selection size, keeping the puzzle's names for its two.
+func sizeLabel(k int) string {
+	switch k {
+	case 2:
+		return "Small Bat"
+	case 12:
+		return "Big Bat"
+	default:
+		return fmt.Sprintf("%d Bat", k)
+	}
 }
 
 // findMaxJoltageN finds the maximum number by selecting exactly n digits.
-// I'm doing it the lazy way in brute-force fashion, since I am lazy and
-// need to get back to work.
-func (b *BatteryBank) findMaxJoltageN(bank string, n int) int64 {
+// The result is only valid until the next call.
+func (b *BatteryBank) findMaxJoltageN(bank string, n int) *big.Int {
 	if len(bank) < n {
-		return 0
+		return b.joltage.SetInt64(0)
 	}
 
-	currentMax := int64(0)
-	pos := 0 // current position in bank
-
-	for i := range n {
-		maxPos := len(bank) - (n - i - 1) - 1 // furthest we can look ahead
+	b.selected = b.Select(b.selected[:0], bank, n)
+	if len(b.selected) < n {
+		return b.joltage.SetInt64(0) // constraints left no selection
+	}
 
-		// Find the largest digit from pos to maxPos
-		bestDigit := byte('0')
-		bestIdx := pos
-		for j := pos; j <= maxPos; j++ {
-			if bank[j] > bestDigit {
-				bestDigit = bank[j]
-				bestIdx = j
-			}
+	// Up to 18 digits fit in an int64
+	if n <= 18 {
+		currentMax := int64(0)
+		for _, i := range b.selected {
+			currentMax = currentMax*10 + int64(bank[i]-'0')
 		}
+		return b.joltage.SetInt64(currentMax)
+	}
 
-		// Append bestDigit to currentMax
-		currentMax = currentMax*10 + int64(bestDigit-'0')
-		pos = bestIdx + 1
+	b.digits = b.digits[:0]
+	for _, i := range b.selected {
+		b.digits = append(b.digits, bank[i])
 	}
+	b.joltage.SetString(string(b.digits), 10)
+	return &b.joltage
+}
 
-	return currentMax
+// parseInts parses a comma separated list of numbers no smaller than minVal.
+func parseInts(s string, minVal int) ([]int, error) {
+	var nums []int
+	for field := range strings.SplitSeq(s, ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		n, err := strconv.Atoi(field)
+		if err != nil || n < minVal {
+			return nil, fmt.Errorf("invalid number %q", field)
+		}
+		nums = append(nums, n)
+	}
+	return nums, nil
 }
 
 func main() {
 	var inputFile string
+	var reportRun bool
+	var selector, sizes, explain, forbid string
+	var constraints Constraints
 
 	flag.StringVar(&inputFile, "input", "day03/input.txt", "input file path")
 	flag.StringVar(&inputFile, "i", "day03/input.txt", "input file path (shorthand)")
+	flag.BoolVar(&reportRun, "report", false, "print a JSON run record for the aoc report")
+	flag.StringVar(&sizes, "k", "2,12", "comma separated numbers of batteries to select from each bank")
+	flag.StringVar(&explain, "explain", "", "print the batteries chosen from each bank as color, brackets or json")
+	flag.StringVar(&selector, "select", "stack", "battery selection algorithm, stack or scan")
+	flag.IntVar(&constraints.MinGap, "gap", 0, "batteries to leave between any two chosen")
+	flag.StringVar(&forbid, "forbid", "", "comma separated positions that can't be chosen, starting at 0")
+	flag.BoolVar(&constraints.Minimize, "min", false, "select the smallest joltage instead of the largest")
+	flag.BoolVar(&constraints.Circular, "circular", false, "treat each bank as a ring, reading from any chosen battery")
 	flag.Parse()
 
 	if inputFile == "" {
@@ -95,6 +172,61 @@ func main() {
 	defer f.Close()
 
 	bank := NewBatteryBank()
+	if bank.Sizes, err = parseInts(sizes, 1); err != nil {
+		log.Fatal(err)
+	}
+	if constraints.Forbidden, err = parseInts(forbid, 0); err != nil {
+		log.Fatal(err)
+	}
+	if constraints.MinGap < 0 {
+		log.Fatal("-gap can't be negative")
+	}
+	switch selector {
+	case "stack":
+	case "scan":
+		bank.Select = SelectScan
+	default:
+		log.Fatalf("unknown selection algorithm %q", selector)
+	}
+	if !constraints.Zero() {
+		bank.Select = constraints.Select
+	}
+
+	if reportRun {
+		if !slices.Contains(bank.Sizes, 2) || !slices.Contains(bank.Sizes, 12) {
+			log.Fatal("-report needs -k to include 2 and 12, the puzzle's two parts")
+		}
+		run, err := report.Measure(3, f, bank.Parse)
+		if err != nil {
+			log.Fatal(err)
+		}
+		run.Answers = []string{bank.Total(2).String(), bank.Total(12).String()}
+		if err := run.Write(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	bank.Explain = explain != ""
 	bank.Parse(f)
-	fmt.Println(bank)
+
+	switch explain {
+	case "":
+	case "color":
+		err = bank.WriteExplainText(os.Stdout, true)
+	case "brackets":
+		err = bank.WriteExplainText(os.Stdout, false)
+	case "json":
+		err = bank.WriteExplainJSON(os.Stdout)
+	default:
+		err = fmt.Errorf("unknown explain format %q", explain)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Keep JSON output machine readable
+	if explain != "json" {
+		fmt.Println(bank)
+	}
 }
@@ -0,0 +1,180 @@
+package main
+
+// Selector appends to dst the indices, in order, of the k batteries in bank
+// whose digits form the largest number, and returns the extended slice.
+type Selector func(dst []int, bank string, k int) []int
+
+// SelectScan is the original selector. For each battery it scans the window
+// of positions that still leave room for the rest, taking the leftmost
+// largest digit, which makes it O(n·k).
+func SelectScan(dst []int, bank string, k int) []int {
+	pos := 0 // current position in bank
+
+	for i := range k {
+		maxPos := len(bank) - (k - i - 1) - 1 // furthest we can look ahead
+
+		// Find the largest digit from pos to maxPos
+		bestDigit := byte('0')
+		bestIdx := pos
+		for j := pos; j <= maxPos; j++ {
+			if bank[j] > bestDigit {
+				bestDigit = bank[j]
+				bestIdx = j
+			}
+		}
+
+		dst = append(dst, bestIdx)
+		pos = bestIdx + 1
+	}
+
+	return dst
+}
+
+// SelectStack picks the same batteries as SelectScan in O(n). Selecting k
+// batteries is dropping len(bank)-k of them, so it keeps a stack of chosen
+// batteries and pops any smaller than the next while drops remain. Equal
+// digits are kept, so the leftmost batteries are chosen as with SelectScan.
+func SelectStack(dst []int, bank string, k int) []int {
+	base := len(dst)
+	drop := len(bank) - k
+
+	for i := range len(bank) {
+		for drop > 0 && len(dst) > base && bank[dst[len(dst)-1]] < bank[i] {
+			dst = dst[:len(dst)-1]
+			drop--
+		}
+		dst = append(dst, i)
+	}
+
+	// Any drops left come off the end, where the digits are smallest
+	return dst[:base+k]
+}
+
+// Constraints limits the batteries a selection may choose. The zero value
+// allows any selection, as SelectScan and SelectStack make.
+type Constraints struct {
+	MinGap    int   // batteries left between any two chosen
+	Forbidden []int // positions that can't be chosen
+	Minimize  bool  // form the smallest number instead of the largest
+	Circular  bool  // the bank wraps, so the number may start at any battery
+}
+
+// Select appends the indices of the k batteries in bank that form the best
+// number under the constraints, in reading order, and returns the extended
+// slice. It returns dst unchanged if no selection satisfies them, or if
+// MinGap is negative.
+//
+// Greedy picking breaks down once a choice can block later ones, so it first
+// works out, for every position, the most batteries that can still be chosen
+// from there on. Each battery is then the best digit whose position leaves
+// room for the rest, the leftmost of equals since that leaves the most room.
+// That is O(n·k), or O(n²·k) for circular banks, which read from each start.
+func (c Constraints) Select(dst []int, bank string, k int) []int {
+	if c.MinGap < 0 {
+		return dst
+	}
+
+	n := len(bank)
+	allowed := make([]bool, n)
+	for i := range allowed {
+		allowed[i] = true
+	}
+	for _, i := range c.Forbidden {
+		if i >= 0 && i < n {
+			allowed[i] = false
+		}
+	}
+
+	if !c.Circular {
+		if picked, ok := c.pick(nil, bank, allowed, 0, n, k); ok {
+			return append(dst, picked...)
+		}
+		return dst
+	}
+
+	// Read the bank from each allowed start, leaving the gap before wrapping
+	// back around to it
+	var best []int
+	var bestDigits string
+	rotated := make([]bool, n)
+	for start := range n {
+		if !allowed[start] || k < 1 {
+			continue
+		}
+
+		bank := bank[start:] + bank[:start]
+		copy(rotated, allowed[start:])
+		copy(rotated[n-start:], allowed[:start])
+
+		picked, ok := c.pick([]int{0}, bank, rotated, 1+c.MinGap, n-c.MinGap, k-1)
+		if !ok {
+			continue
+		}
+
+		digits := make([]byte, len(picked))
+		for i, p := range picked {
+			digits[i] = bank[p]
+			picked[i] = (p + start) % n
+		}
+		if best == nil || c.better(string(digits), bestDigits) {
+			best, bestDigits = picked, string(digits)
+		}
+	}
+
+	return append(dst, best...)
+}
+
+// pick appends to dst the best k batteries from positions from to to-1, or
+// reports false if there aren't k that keep the gap.
+func (c Constraints) pick(dst []int, bank string, allowed []bool, from, to, k int) ([]int, bool) {
+	if k == 0 {
+		return dst, true
+	}
+	if from >= to {
+		return dst, false
+	}
+
+	// most[i] is the most batteries that can be chosen from positions i to
+	// to-1, with zeros past the end so choosing near it needs no checks
+	most := make([]int, to+c.MinGap+2)
+	for i := to - 1; i >= from; i-- {
+		most[i] = most[i+1]
+		if allowed[i] {
+			most[i] = max(most[i], 1+most[i+1+c.MinGap])
+		}
+	}
+	if most[from] < k {
+		return dst, false
+	}
+
+	pos := from
+	for chosen := range k {
+		left := k - chosen - 1
+		best := -1
+		for p := pos; p < to; p++ {
+			if !allowed[p] || most[p+1+c.MinGap] < left {
+				continue
+			}
+			if best < 0 || c.better(bank[p:p+1], bank[best:best+1]) {
+				best = p
+			}
+		}
+		dst = append(dst, best)
+		pos = best + 1 + c.MinGap
+	}
+	return dst, true
+}
+
+// better reports whether digits a form a better number than b of the same
+// length.
+func (c Constraints) better(a, b string) bool {
+	if c.Minimize {
+		return a < b
+	}
+	return a > b
+}
+
+// Zero reports whether the constraints allow any selection.
+func (c Constraints) Zero() bool {
+	return c.MinGap == 0 && len(c.Forbidden) == 0 && !c.Minimize && !c.Circular
+}
@@ -0,0 +1,157 @@
+package main_test
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+
+	main "github.com/lcox74/aoc25/day03"
+	"github.com/stretchr/testify/require"
+)
+
+// randomBank returns n random battery digits, drawn from 1 to maxDigit so
+// smaller maxDigit gives more ties.
+func randomBank(rng *rand.Rand, n int, maxDigit byte) string {
+	var sb strings.Builder
+	for range n {
+		sb.WriteByte('1' + byte(rng.IntN(int(maxDigit))))
+	}
+	return sb.String()
+}
+
+func TestSelectStackMatchesScan(t *testing.T) {
+	rng := rand.New(rand.NewPCG(43, 3))
+
+	for range 500 {
+		bank := randomBank(rng, 1+rng.IntN(60), byte(1+rng.IntN(9)))
+		k := rng.IntN(len(bank) + 1)
+
+		want := main.SelectScan(nil, bank, k)
+		got := main.SelectStack(nil, bank, k)
+		require.True(t, slices.Equal(want, got), "bank %s, k %d: expected %v, got %v", bank, k, want, got)
+	}
+
+	// Appends after what's already there
+	require.Equal(t, []int{7, 0, 14}, main.SelectStack([]int{7}, "811111111111119", 2))
+}
+
+func BenchmarkSelect(b *testing.B) {
+	bank := randomBank(rand.New(rand.NewPCG(43, 3)), 100_000, 9)
+	selectors := []struct {
+		name string
+		fn   main.Selector
+	}{
+		{"scan", main.SelectScan},
+		{"stack", main.SelectStack},
+	}
+
+	for _, k := range []int{12, 1_000, 10_000} {
+		for _, s := range selectors {
+			b.Run(fmt.Sprintf("%s/k=%d", s.name, k), func(b *testing.B) {
+				dst := make([]int, 0, len(bank))
+				for b.Loop() {
+					dst = s.fn(dst[:0], bank, k)
+				}
+				require.True(b, slices.IsSorted(dst))
+			})
+		}
+	}
+}
+
+// bruteForce tries every choice of k batteries from bank, and for circular
+// banks every chosen battery to read from, returning the best digits under
+// c or "" if nothing satisfies it.
+func bruteForce(bank string, k int, c main.Constraints) string {
+	n := len(bank)
+	best := ""
+	var choose func(from int, chosen []int)
+	choose = func(from int, chosen []int) {
+		if len(chosen) < k {
+			for i := from; i < n; i++ {
+				if !slices.Contains(c.Forbidden, i) {
+					choose(i+1, append(chosen, i))
+				}
+			}
+			return
+		}
+
+		starts := 1
+		if c.Circular {
+			starts = k
+		}
+		for s := range starts {
+			order := append(slices.Clone(chosen[s:]), chosen[:s]...)
+			var digits []byte
+			ok := true
+			for i, p := range order {
+				digits = append(digits, bank[p])
+				if i > 0 && (p-order[i-1]+n)%n <= c.MinGap {
+					ok = false
+				}
+			}
+			if c.Circular && k > 1 && (order[0]-order[k-1]+n)%n <= c.MinGap {
+				ok = false
+			}
+			if ok && (best == "" || (c.Minimize && string(digits) < best) || (!c.Minimize && string(digits) > best)) {
+				best = string(digits)
+			}
+		}
+	}
+	choose(0, nil)
+	return best
+}
+
+func TestConstraintsMatchBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewPCG(46, 3))
+
+	for range 2000 {
+		bank := randomBank(rng, 1+rng.IntN(9), byte(1+rng.IntN(9)))
+		k := 1 + rng.IntN(len(bank))
+		c := main.Constraints{
+			MinGap:   rng.IntN(3),
+			Minimize: rng.IntN(2) == 0,
+			Circular: rng.IntN(2) == 0,
+		}
+		for i := range len(bank) {
+			if rng.IntN(5) == 0 {
+				c.Forbidden = append(c.Forbidden, i)
+			}
+		}
+
+		want := bruteForce(bank, k, c)
+		indices := c.Select(nil, bank, k)
+		if want == "" {
+			require.Empty(t, indices, "bank %s, k %d, %+v", bank, k, c)
+			continue
+		}
+
+		var got []byte
+		for _, i := range indices {
+			require.NotContains(t, c.Forbidden, i)
+			got = append(got, bank[i])
+		}
+		require.Equal(t, want, string(got), "bank %s, k %d, %+v: indices %v", bank, k, c, indices)
+	}
+}
+
+func TestConstraintsZeroMatchesStack(t *testing.T) {
+	rng := rand.New(rand.NewPCG(46, 4))
+
+	for range 200 {
+		bank := randomBank(rng, 1+rng.IntN(60), 9)
+		k := rng.IntN(len(bank) + 1)
+		want := main.SelectStack(nil, bank, k)
+		got := main.Constraints{}.Select(nil, bank, k)
+		require.True(t, slices.Equal(want, got), "bank %s, k %d: expected %v, got %v", bank, k, want, got)
+	}
+}
+
+func TestConstraintsNegativeGap(t *testing.T) {
+	dst := []int{7}
+	for _, c := range []main.Constraints{{MinGap: -1}, {MinGap: -2}, {MinGap: -1, Circular: true}} {
+		got := c.Select(dst, "987654321111111", 2)
+		require.Equal(t, []int{7}, got, "gap %d", c.MinGap)
+	}
+}
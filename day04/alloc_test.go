@@ -0,0 +1,46 @@
+package main_test
+
+import (
+	"io"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	main "github.com/lcox74/aoc25/day04"
+	"github.com/lcox74/aoc25/internal/report"
+)
+
+// budget for parsing and solving generatedInput, about 25% over measured.
+var budget = report.Budget{Allocs: 160, Bytes: 200_000}
+
+// generatedInput returns a 100x100 grid where about 60% of cells are rolls.
+func generatedInput() string {
+	rng := rand.New(rand.NewPCG(29, 4))
+
+	var sb strings.Builder
+	for range 100 {
+		for range 100 {
+			if rng.IntN(10) < 6 {
+				sb.WriteByte('@')
+			} else {
+				sb.WriteByte('.')
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+func TestAllocBudget(t *testing.T) {
+	budget.Check(t, generatedInput(), func(r io.Reader) {
+		main.NewPrintDept().Parse(r)
+	})
+}
+
+func BenchmarkParse(b *testing.B) {
+	input := generatedInput()
+	b.ReportAllocs()
+	for b.Loop() {
+		main.NewPrintDept().Parse(strings.NewReader(input))
+	}
+}
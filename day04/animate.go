@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Palette indices shared by every frame, waves follow from firstWave.
+const (
+	background = iota
+	remaining
+	firstWave
+)
+
+// WaveMap returns the wave each cell's roll was removed in for every grid
+// index, starting at 1. Cells that never held a roll are 0, and rolls that
+// were never removed are -1.
+func (p *PrintDept) WaveMap() []int {
+	waves := make([]int, len(p.Grid))
+	for idx, c := range p.Grid {
+		if c == p.Roll {
+			waves[idx] = -1
+		}
+	}
+	for wave, cells := range p.Waves {
+		for _, idx := range cells {
+			waves[idx] = wave + 1
+		}
+	}
+	return waves
+}
+
+// Frames returns how many frames the animation has, the grid before any
+// removal and then one after each wave.
+func (p *PrintDept) Frames() int {
+	return len(p.Waves) + 1
+}
+
+// Frame draws the grid after the first n waves with every cell scale pixels
+// square. Removed rolls are coloured by their wave from red (first) to blue
+// (last) as in the SVG, and rolls still standing are grey. The last frame is
+// the wave number heatmap.
+func (p *PrintDept) Frame(n, scale int) *image.Paletted {
+	img := image.NewPaletted(image.Rect(0, 0, p.Width*scale, p.Height*scale), p.palette())
+	waves := p.WaveMap()
+
+	for idx, wave := range waves {
+		var c uint8
+		switch {
+		case wave == 0:
+			continue
+		case wave > 0 && wave <= n:
+			c = p.waveIndex(wave)
+		default:
+			c = remaining
+		}
+
+		x, y := idx%p.Width*scale, idx/p.Width*scale
+		for dy := range scale {
+			row := img.PixOffset(x, y+dy)
+			for dx := range scale {
+				img.Pix[row+dx] = c
+			}
+		}
+	}
+	return img
+}
+
+// WriteGIF writes the removal waves as an animated GIF, showing each frame
+// for delay and looping forever.
+func (p *PrintDept) WriteGIF(w io.Writer, scale int, delay time.Duration) error {
+	anim := &gif.GIF{}
+	for n := range p.Frames() {
+		anim.Image = append(anim.Image, p.Frame(n, scale))
+		anim.Delay = append(anim.Delay, int(delay/(10*time.Millisecond)))
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+// WritePNGFrames writes every frame to dir as frame0000.png, frame0001.png
+// and so on, creating dir if needed.
+func (p *PrintDept) WritePNGFrames(dir string, scale int) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for n := range p.Frames() {
+		f, err := os.Create(filepath.Join(dir, fmt.Sprintf("frame%04d.png", n)))
+		if err != nil {
+			return err
+		}
+		err = png.Encode(f, p.Frame(n, scale))
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteHeatmap writes a PNG of every removed roll coloured by its wave.
+func (p *PrintDept) WriteHeatmap(w io.Writer, scale int) error {
+	return png.Encode(w, p.Frame(len(p.Waves), scale))
+}
+
+// WriteANSI plays the removal waves in a terminal, redrawing the grid for
+// each frame and waiting delay between them. Removed rolls are drawn as
+// blocks in their wave colour.
+func (p *PrintDept) WriteANSI(w io.Writer, delay time.Duration) error {
+	bw := bufio.NewWriter(w)
+	palette := p.palette()
+	waves := p.WaveMap()
+
+	for n := range p.Frames() {
+		if n > 0 {
+			time.Sleep(delay)
+		}
+		fmt.Fprintf(bw, "\x1b[H\x1b[2Jwave %d/%d\n", n, len(p.Waves))
+		for y := range p.Height {
+			for x := range p.Width {
+				switch wave := waves[p.Width*y+x]; {
+				case wave == 0:
+					bw.WriteByte(p.Empty)
+				case wave > 0 && wave <= n:
+					r, g, b, _ := palette[p.waveIndex(wave)].RGBA()
+					fmt.Fprintf(bw, "\x1b[38;2;%d;%d;%dm█\x1b[0m", r>>8, g>>8, b>>8)
+				default:
+					bw.WriteByte(p.Roll)
+				}
+			}
+			bw.WriteByte('\n')
+		}
+		if err := bw.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// palette returns the frame colours, with one hue per wave up to the 256
+// colours a paletted image can hold.
+func (p *PrintDept) palette() color.Palette {
+	hues := min(len(p.Waves), 256-firstWave)
+	palette := color.Palette{
+		background: color.RGBA{0x0f, 0x0f, 0x23, 0xff},
+		remaining:  color.RGBA{0x77, 0x77, 0x77, 0xff},
+	}
+	for i := range hues {
+		palette = append(palette, hsl(240*float64(i)/float64(max(hues-1, 1)), 0.8, 0.55))
+	}
+	return palette
+}
+
+// waveIndex returns the palette index for a wave, starting at 1. Waves
+// share hues when there are more of them than the palette holds.
+func (p *PrintDept) waveIndex(wave int) uint8 {
+	hues := min(len(p.Waves), 256-firstWave)
+	return uint8(firstWave + (wave-1)*(hues-1)/max(len(p.Waves)-1, 1))
+}
+
+// hsl converts a hue in degrees, saturation and lightness to an opaque
+// colour.
+func hsl(h, s, l float64) color.RGBA {
+	c := (1 - math.Abs(2*l-1)) * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := l - c/2
+
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g = c, x
+	case h < 120:
+		r, g = x, c
+	case h < 180:
+		g, b = c, x
+	case h < 240:
+		g, b = x, c
+	case h < 300:
+		r, b = x, c
+	default:
+		r, b = c, x
+	}
+	return color.RGBA{
+		uint8(math.Round((r + m) * 255)),
+		uint8(math.Round((g + m) * 255)),
+		uint8(math.Round((b + m) * 255)),
+		0xff,
+	}
+}
@@ -0,0 +1,68 @@
+package main_test
+
+import (
+	"bytes"
+	"image/gif"
+	"image/png"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	main "github.com/lcox74/aoc25/day04"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaveMap(t *testing.T) {
+	dept := main.NewPrintDept()
+	dept.Parse(strings.NewReader(exampleInput))
+	waves := dept.WaveMap()
+
+	require.Equal(t, 0, waves[0])       // never a roll
+	require.Equal(t, 1, waves[2])       // first row corner roll goes first
+	require.Equal(t, -1, waves[4*10+4]) // centre of the rolls left standing
+	require.Equal(t, len(dept.Waves), slices.Max(waves))
+	for wave, cells := range dept.Waves {
+		for _, idx := range cells {
+			require.Equal(t, wave+1, waves[idx])
+		}
+	}
+}
+
+func TestAnimation(t *testing.T) {
+	dept := main.NewPrintDept()
+	dept.Parse(strings.NewReader(exampleInput))
+	require.Equal(t, len(dept.Waves)+1, dept.Frames())
+
+	var buf bytes.Buffer
+	require.NoError(t, dept.WriteGIF(&buf, 3, 0))
+	anim, err := gif.DecodeAll(&buf)
+	require.NoError(t, err)
+	require.Len(t, anim.Image, dept.Frames())
+	require.Equal(t, 30, anim.Image[0].Bounds().Dx())
+
+	// Frames only ever gain wave colours, and the last is the heatmap
+	var heatmap bytes.Buffer
+	require.NoError(t, dept.WriteHeatmap(&heatmap, 3))
+	still, err := png.Decode(&heatmap)
+	require.NoError(t, err)
+	last := anim.Image[len(anim.Image)-1]
+	for y := range 30 {
+		for x := range 30 {
+			require.Equal(t, still.At(x, y), last.At(x, y), "pixel %d,%d", x, y)
+		}
+	}
+
+	dir := filepath.Join(t.TempDir(), "frames")
+	require.NoError(t, dept.WritePNGFrames(dir, 1))
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, dept.Frames())
+	require.Equal(t, "frame0000.png", entries[0].Name())
+
+	buf.Reset()
+	require.NoError(t, dept.WriteANSI(&buf, 0))
+	require.Equal(t, dept.Frames(), strings.Count(buf.String(), "\x1b[2J"))
+	require.Contains(t, buf.String(), "wave 9/9\n")
+}
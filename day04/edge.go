@@ -0,0 +1,53 @@
+package main
+
+import "fmt"
+
+// Edge decides what the kernel sees past the edge of the grid.
+type Edge string
+
+const (
+	EdgeEmpty  Edge = "empty"  // nothing past the edge
+	EdgeWall   Edge = "wall"   // a roll in every cell past the edge
+	EdgeTorus  Edge = "torus"  // the grid wraps around to the opposite edge
+	EdgeMirror Edge = "mirror" // the grid reflected about its edge rows and columns
+)
+
+// outside is passed to forEachNeighbor callbacks for a wall cell past the
+// edge of the grid.
+const outside = -1
+
+// ParseEdge returns the edge mode named s, e.g. "torus".
+func ParseEdge(s string) (Edge, error) {
+	switch e := Edge(s); e {
+	case EdgeEmpty, EdgeWall, EdgeTorus, EdgeMirror:
+		return e, nil
+	}
+	return "", fmt.Errorf("unknown edge mode %q", s)
+}
+
+// locate maps a coordinate along an axis of length n onto the grid, and
+// reports false if the edge mode leaves it off the grid.
+func (e Edge) locate(i, n int) (int, bool) {
+	if i >= 0 && i < n {
+		return i, true
+	}
+
+	switch e {
+	case EdgeTorus:
+		return (i%n + n) % n, true
+	case EdgeMirror:
+		// Reflect about the edge cells, so -1 is 1, bouncing between the
+		// edges for kernels wider than the grid
+		if n == 1 {
+			return 0, true
+		}
+		period := 2 * (n - 1)
+		i = (i%period + period) % period
+		if i >= n {
+			i = period - i
+		}
+		return i, true
+	default:
+		return 0, false
+	}
+}
@@ -1,6 +1,7 @@
 package main_test
 
 import (
+	"math/rand/v2"
 	"strings"
 	"testing"
 
@@ -30,3 +31,122 @@ func TestExample(t *testing.T) {
 	// Part 2: 43 total rolls removed after iterative removal
 	require.Equal(t, 43, dept.TotalRemoved)
 }
+
+// rescanWaves removes accessible rolls round by round, rescanning the whole
+// grid each time, using the kernel, threshold, comparison and symbols of cfg.
+func rescanWaves(input string, cfg *main.PrintDept) [][]int {
+	lines := strings.Fields(input)
+	w, h := len(lines[0]), len(lines)
+	grid := []byte(strings.Join(lines, ""))
+	half := cfg.KernelSize / 2
+
+	var waves [][]int
+	for {
+		var wave []int
+		for idx, c := range grid {
+			if c != cfg.Roll {
+				continue
+			}
+			x, y, count := idx%w, idx/w, 0
+			for ky := range cfg.KernelSize {
+				for kx := range cfg.KernelSize {
+					if rollAt(grid, w, h, x+kx-half, y+ky-half, cfg) {
+						count += cfg.Kernel[ky*cfg.KernelSize+kx]
+					}
+				}
+			}
+			if compare(cfg.Compare, count, cfg.Threshold) {
+				wave = append(wave, idx)
+			}
+		}
+		if len(wave) == 0 {
+			return waves
+		}
+		for _, idx := range wave {
+			grid[idx] = cfg.Empty
+		}
+		waves = append(waves, wave)
+	}
+}
+
+// rollAt reports whether the kernel sees a roll at (x, y), which may be past
+// the edge of the grid.
+func rollAt(grid []byte, w, h, x, y int, cfg *main.PrintDept) bool {
+	if x < 0 || x >= w || y < 0 || y >= h {
+		switch cfg.Edge {
+		case main.EdgeWall:
+			return true
+		case main.EdgeTorus:
+			x, y = ((x%w)+w)%w, ((y%h)+h)%h
+		case main.EdgeMirror:
+			x, y = reflect(x, w), reflect(y, h)
+		default:
+			return false
+		}
+	}
+	return grid[y*w+x] == cfg.Roll
+}
+
+// reflect bounces i between the ends of an axis of length n.
+func reflect(i, n int) int {
+	if n == 1 {
+		return 0
+	}
+	for i < 0 || i >= n {
+		if i < 0 {
+			i = -i
+		}
+		if i >= n {
+			i = 2*(n-1) - i
+		}
+	}
+	return i
+}
+
+// compare evaluates a comparison without going through PrintDept.
+func compare(c main.Comparison, count, threshold int) bool {
+	switch c {
+	case main.Less:
+		return count < threshold
+	case main.LessEqual:
+		return count <= threshold
+	case main.Greater:
+		return count > threshold
+	case main.GreaterEqual:
+		return count >= threshold
+	case main.Equal:
+		return count == threshold
+	default:
+		return count != threshold
+	}
+}
+
+// randomGrid returns a grid of up to 30 by 30 cells, about three quarters
+// of them roll.
+func randomGrid(rng *rand.Rand, roll, empty byte) string {
+	var sb strings.Builder
+	w, h := 1+rng.IntN(30), 1+rng.IntN(30)
+	for range h {
+		for range w {
+			sb.WriteByte([]byte{empty, roll}[min(rng.IntN(4), 1)])
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+func TestWavesMatchRescan(t *testing.T) {
+	rng := rand.New(rand.NewPCG(47, 4))
+
+	inputs := []string{exampleInput}
+	for range 20 {
+		inputs = append(inputs, randomGrid(rng, '@', '.'))
+	}
+
+	for _, input := range inputs {
+		dept := main.NewPrintDept()
+		dept.Parse(strings.NewReader(input))
+		want := rescanWaves(input, dept)
+		require.Equal(t, want, dept.Waves, "input:\n%s", input)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Comparison decides whether a roll is accessible by comparing its
+// neighbour count with the threshold.
+type Comparison string
+
+const (
+	Less         Comparison = "<"
+	LessEqual    Comparison = "<="
+	Greater      Comparison = ">"
+	GreaterEqual Comparison = ">="
+	Equal        Comparison = "=="
+	NotEqual     Comparison = "!="
+)
+
+// ParseComparison returns the comparison written as s, e.g. "<=".
+func ParseComparison(s string) (Comparison, error) {
+	switch c := Comparison(s); c {
+	case Less, LessEqual, Greater, GreaterEqual, Equal, NotEqual:
+		return c, nil
+	}
+	return "", fmt.Errorf("unknown comparison %q", s)
+}
+
+// holds reports whether count compares true against threshold.
+func (c Comparison) holds(count, threshold int) bool {
+	switch c {
+	case LessEqual:
+		return count <= threshold
+	case Greater:
+		return count > threshold
+	case GreaterEqual:
+		return count >= threshold
+	case Equal:
+		return count == threshold
+	case NotEqual:
+		return count != threshold
+	default:
+		return count < threshold
+	}
+}
+
+// ParseKernel reads a square kernel with an odd number of rows, one row per
+// line with whitespace between the weights. The centre is the roll being
+// checked, and a roll at any other position adds its weight to the count.
+// Blank lines and lines starting with # are skipped.
+//
+//	# count rolls up to two cells away, the nearest ones twice
+//	1 1 1 1 1
+//	1 2 2 2 1
+//	1 2 0 2 1
+//	1 2 2 2 1
+//	1 1 1 1 1
+func ParseKernel(r io.Reader) (kernel []int, size int, err error) {
+	scanner := bufio.NewScanner(r)
+	rows, width := 0, 0
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		for _, field := range fields {
+			weight, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, 0, fmt.Errorf("kernel row %d: invalid weight %q", rows+1, field)
+			}
+			kernel = append(kernel, weight)
+		}
+		rows++
+		if rows == 1 {
+			width = len(fields)
+		} else if len(fields) != width {
+			return nil, 0, fmt.Errorf("kernel row %d: rows have different lengths", rows)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	if rows == 0 || rows%2 == 0 || width != rows {
+		return nil, 0, fmt.Errorf("kernel must be square with an odd size, got %d rows of %d", rows, width)
+	}
+	return kernel, rows, nil
+}
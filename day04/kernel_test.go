@@ -0,0 +1,132 @@
+package main_test
+
+import (
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	main "github.com/lcox74/aoc25/day04"
+	"github.com/stretchr/testify/require"
+)
+
+// weightedKernel counts rolls up to two cells away, the nearest ones twice.
+const weightedKernel = `# nearest rolls count twice
+1 1 1 1 1
+1 2 2 2 1
+1 2 0 2 1
+1 2 2 2 1
+1 1 1 1 1
+`
+
+func TestParseKernel(t *testing.T) {
+	kernel, size, err := main.ParseKernel(strings.NewReader(weightedKernel))
+	require.NoError(t, err)
+	require.Equal(t, 5, size)
+	require.Len(t, kernel, 25)
+	require.Equal(t, []int{1, 2, 0, 2, 1}, kernel[10:15])
+
+	kernel, size, err = main.ParseKernel(strings.NewReader("\n1 -1 1\n\n0 0 0\n1 -1 1\n"))
+	require.NoError(t, err)
+	require.Equal(t, 3, size)
+	require.Equal(t, []int{1, -1, 1, 0, 0, 0, 1, -1, 1}, kernel)
+
+	for name, input := range map[string]string{
+		"empty":      "# nothing\n",
+		"even":       "1 1\n1 1\n",
+		"not square": "1 1 1\n",
+		"ragged":     "1 1 1\n1 0\n1 1 1\n",
+		"weight":     "1 1 1\n1 x 1\n1 1 1\n",
+	} {
+		_, _, err := main.ParseKernel(strings.NewReader(input))
+		require.Error(t, err, name)
+	}
+}
+
+func TestParseComparison(t *testing.T) {
+	for _, s := range []string{"<", "<=", ">", ">=", "==", "!="} {
+		c, err := main.ParseComparison(s)
+		require.NoError(t, err)
+		require.Equal(t, main.Comparison(s), c)
+	}
+	_, err := main.ParseComparison("=>")
+	require.Error(t, err)
+}
+
+func TestConfigMatchesRescan(t *testing.T) {
+	weighted, size, err := main.ParseKernel(strings.NewReader(weightedKernel))
+	require.NoError(t, err)
+
+	configs := []func(*main.PrintDept){
+		func(p *main.PrintDept) { p.Kernel, p.KernelSize, p.Threshold = weighted, size, 20 },
+		func(p *main.PrintDept) { p.Compare, p.Threshold = main.LessEqual, 2 },
+		func(p *main.PrintDept) { p.Compare, p.Threshold = main.Greater, 5 },
+		func(p *main.PrintDept) { p.Compare, p.Threshold = main.GreaterEqual, 7 },
+		func(p *main.PrintDept) { p.Compare, p.Threshold = main.Equal, 3 },
+		func(p *main.PrintDept) { p.Compare, p.Threshold = main.NotEqual, 4 },
+		func(p *main.PrintDept) {
+			// Diagonal rolls block, rolls beside help
+			p.Kernel, p.Threshold = []int{2, -1, 2, -1, 0, -1, 2, -1, 2}, 3
+		},
+		func(p *main.PrintDept) { p.Roll, p.Empty = '#', ' ' },
+	}
+
+	rng := rand.New(rand.NewPCG(48, 4))
+	for i, configure := range configs {
+		for range 20 {
+			dept := main.NewPrintDept()
+			configure(dept)
+			input := randomGrid(rng, dept.Roll, '.')
+			dept.Parse(strings.NewReader(input))
+
+			want := rescanWaves(input, dept)
+			require.Equal(t, want, dept.Waves, "config %d, input:\n%s", i, input)
+			if len(want) > 0 {
+				require.Len(t, want[0], dept.AccessibleRolls, "config %d", i)
+			}
+		}
+	}
+}
+
+func TestSymbols(t *testing.T) {
+	dept := main.NewPrintDept()
+	dept.Roll, dept.Empty = '#', '_'
+	input := strings.NewReplacer("@", "#", ".", "_").Replace(exampleInput)
+	dept.Parse(strings.NewReader(input))
+
+	require.Equal(t, 13, dept.AccessibleRolls)
+	require.Equal(t, 43, dept.TotalRemoved)
+	require.NotContains(t, string(dept.Grid), "@")
+}
+
+func TestEdgesMatchRescan(t *testing.T) {
+	weighted, size, err := main.ParseKernel(strings.NewReader(weightedKernel))
+	require.NoError(t, err)
+
+	rng := rand.New(rand.NewPCG(50, 4))
+	for _, edge := range []main.Edge{main.EdgeEmpty, main.EdgeWall, main.EdgeTorus, main.EdgeMirror} {
+		for i := range 40 {
+			dept := main.NewPrintDept()
+			dept.Edge = edge
+			if i%2 == 1 {
+				// Wider than the smallest grids, so the kernel wraps or
+				// reflects more than once
+				dept.Kernel, dept.KernelSize, dept.Threshold = weighted, size, 20
+			}
+			input := randomGrid(rng, '@', '.')
+			dept.Parse(strings.NewReader(input))
+
+			want := rescanWaves(input, dept)
+			require.Equal(t, want, dept.Waves, "edge %s, input:\n%s", edge, input)
+		}
+	}
+}
+
+func TestParseEdge(t *testing.T) {
+	for _, s := range []string{"empty", "wall", "torus", "mirror"} {
+		e, err := main.ParseEdge(s)
+		require.NoError(t, err)
+		require.Equal(t, main.Edge(s), e)
+	}
+	_, err := main.ParseEdge("klein")
+	require.Error(t, err)
+}
@@ -8,18 +8,31 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/lcox74/aoc25/internal/report"
 )
 
 // PrintDept finds accessible paper rolls in the printing department.
 // A roll is accessible if fewer than 4 rolls are in adjacent positions.
+// The kernel weighting the positions counted, the threshold and comparison,
+// and the symbols for rolls and empty cells can all be changed.
 type PrintDept struct {
 	Grid            []byte
 	Width           int
 	Height          int
-	Kernel          []int
-	KernelSize      int
-	AccessibleRolls int // Part 1: initial accessible count
-	TotalRemoved    int // Part 2: total removed after iterative removal
+	Kernel          []int      // weight of a roll at each position, KernelSize rows of KernelSize
+	KernelSize      int        // odd, so the kernel centres on the roll checked
+	Threshold       int        // neighbour count compared against
+	Compare         Comparison // how the count compares for a roll to be accessible
+	Roll            byte       // symbol for a paper roll
+	Empty           byte       // symbol a removed roll leaves
+	Edge            Edge       // what the kernel sees past the edge of the grid
+	AccessibleRolls int        // Part 1: initial accessible count
+	TotalRemoved    int        // Part 2: total removed after iterative removal
+	Waves           [][]int    // Grid indices removed in each round of part 2
 }
 
 func NewPrintDept() *PrintDept {
@@ -27,11 +40,22 @@ func NewPrintDept() *PrintDept {
 		// Default 3x3 kernel: check all 8 neighbors, skip center
 		Kernel:     []int{1, 1, 1, 1, 0, 1, 1, 1, 1},
 		KernelSize: 3,
+		Threshold:  4,
+		Compare:    Less,
+		Roll:       '@',
+		Empty:      '.',
+		Edge:       EdgeEmpty,
 	}
 }
 
 // Parse reads the grid from r and counts accessible rolls.
 func (p *PrintDept) Parse(r io.Reader) {
+	p.parseInput(r)
+	p.solve()
+}
+
+// parseInput reads the grid, padding short lines to the first line's width.
+func (p *PrintDept) parseInput(r io.Reader) {
 	scanner := bufio.NewScanner(r)
 	var lines []string
 
@@ -58,7 +82,10 @@ func (p *PrintDept) Parse(r io.Reader) {
 			}
 		}
 	}
+}
 
+// solve counts the accessible rolls and then removes them in waves.
+func (p *PrintDept) solve() {
 	p.AccessibleRolls = p.countAccessibleRolls()
 	p.removeAllAccessible()
 }
@@ -71,18 +98,18 @@ func (p *PrintDept) String() string {
 	)
 }
 
-// countAccessibleRolls counts rolls with fewer than 4 adjacent rolls.
+// countAccessibleRolls counts rolls whose neighbour count passes the
+// threshold, by default those with fewer than 4 adjacent rolls.
 func (p *PrintDept) countAccessibleRolls() int {
 	count := 0
 	for y := range p.Height {
 		for x := range p.Width {
 			idx := p.Width*y + x
-			if p.Grid[idx] != '@' {
+			if p.Grid[idx] != p.Roll {
 				continue
 			}
 
-			neighborCount := p.getNeighborCount(x, y)
-			if neighborCount < 4 {
+			if p.accessible(p.getNeighborCount(x, y)) {
 				count++
 			}
 		}
@@ -90,72 +117,192 @@ func (p *PrintDept) countAccessibleRolls() int {
 	return count
 }
 
+// accessible reports whether a roll with the given neighbour count can be
+// reached.
+func (p *PrintDept) accessible(count int) bool {
+	return p.Compare.holds(count, p.Threshold)
+}
+
 // removeAllAccessible iteratively removes accessible rolls until none remain.
+// All rolls accessible at the start of a round are removed together. Rather
+// than rescanning the grid each round, it keeps every roll's neighbour count
+// and only updates the rolls around the ones just removed, so the work
+// follows the removals instead of rounds times the grid. Every roll left was
+// inaccessible at the start of the round, so only those whose count changed
+// need checking for the next.
 func (p *PrintDept) removeAllAccessible() {
-	for {
-		toRemove := p.findAccessiblePositions()
-		if len(toRemove) == 0 {
-			break
+	counts := make([]int, len(p.Grid))
+	changed := make([]bool, len(p.Grid))
+	var nearby []int
+
+	// Every wave is a run of one queue holding each roll at most once. The
+	// rolls whose count changed are gathered past the current wave, where
+	// there is always room for every roll still standing, and filtered down
+	// to the next wave in place
+	rolls := 0
+	for _, c := range p.Grid {
+		if c == p.Roll {
+			rolls++
 		}
+	}
+	queue := make([]int, 0, rolls)
 
-		for _, idx := range toRemove {
-			p.Grid[idx] = '.'
+	for idx, c := range p.Grid {
+		if c != p.Roll {
+			continue
+		}
+		counts[idx] = p.getNeighborCount(idx%p.Width, idx/p.Width)
+		if p.accessible(counts[idx]) {
+			queue = append(queue, idx)
 		}
-		p.TotalRemoved += len(toRemove)
 	}
-}
 
-// findAccessiblePositions returns indices of all currently accessible rolls.
-func (p *PrintDept) findAccessiblePositions() []int {
-	var positions []int
-	for y := range p.Height {
-		for x := range p.Width {
-			idx := p.Width*y + x
-			if p.Grid[idx] != '@' {
-				continue
+	for start := 0; start < len(queue); {
+		end := len(queue)
+		wave := queue[start:end:end]
+		for _, idx := range wave {
+			p.Grid[idx] = p.Empty
+		}
+		p.TotalRemoved += len(wave)
+		p.Waves = append(p.Waves, wave)
+
+		for _, removed := range wave {
+			nearby = p.nearby(nearby[:0], removed)
+			for _, idx := range nearby {
+				if p.Grid[idx] != p.Roll {
+					continue
+				}
+				weight := p.weightOf(idx, removed)
+				if weight == 0 {
+					continue
+				}
+				counts[idx] -= weight
+				if !changed[idx] {
+					changed[idx] = true
+					queue = append(queue, idx)
+				}
 			}
+		}
 
-			neighborCount := p.getNeighborCount(x, y)
-			if neighborCount < 4 {
-				positions = append(positions, idx)
+		next := queue[end:end]
+		for _, idx := range queue[end:] {
+			changed[idx] = false
+			if p.accessible(counts[idx]) {
+				next = append(next, idx)
 			}
 		}
+		queue = queue[:end+len(next)]
+
+		// Keep each wave in grid order, as a full scan would find them
+		slices.Sort(next)
+		start = end
 	}
-	return positions
+}
+
+// nearby appends the index of every cell whose kernel could reach the cell
+// at idx to dst, each once, and returns the extended slice. Reflected cells
+// are never further away than the cell itself, so only the torus reaches
+// past the box around idx.
+func (p *PrintDept) nearby(dst []int, idx int) []int {
+	x, y := idx%p.Width, idx/p.Width
+	halfK := p.KernelSize / 2
+
+	if p.Edge != EdgeTorus {
+		for ny := max(y-halfK, 0); ny <= min(y+halfK, p.Height-1); ny++ {
+			for nx := max(x-halfK, 0); nx <= min(x+halfK, p.Width-1); nx++ {
+				dst = append(dst, p.Width*ny+nx)
+			}
+		}
+		return dst
+	}
+
+	start := len(dst)
+	for dy := -halfK; dy <= halfK; dy++ {
+		ny, _ := p.Edge.locate(y+dy, p.Height)
+		for dx := -halfK; dx <= halfK; dx++ {
+			nx, _ := p.Edge.locate(x+dx, p.Width)
+			dst = append(dst, p.Width*ny+nx)
+		}
+	}
+
+	// A kernel wider than the grid wraps onto the same cells more than once
+	if p.KernelSize > p.Width || p.KernelSize > p.Height {
+		slices.Sort(dst[start:])
+		dst = slices.Compact(dst)
+	}
+	return dst
+}
+
+// weightOf returns how much the cell at target adds to the neighbour count
+// of the cell at idx when it holds a roll.
+func (p *PrintDept) weightOf(idx, target int) int {
+	weight := 0
+	p.forEachNeighbor(idx%p.Width, idx/p.Width, func(n, w int) {
+		if n == target {
+			weight += w
+		}
+	})
+	return weight
 }
 
 // getNeighborCount applies the kernel to count adjacent rolls.
 func (p *PrintDept) getNeighborCount(x, y int) int {
 	count := 0
+	p.forEachNeighbor(x, y, func(idx, weight int) {
+		if idx == outside || p.Grid[idx] == p.Roll {
+			count += weight
+		}
+	})
+	return count
+}
+
+// forEachNeighbor calls fn with the index of each cell the kernel covers
+// around (x, y), and how much a roll there counts. Cells past the edge are
+// mapped onto the grid by the edge mode, skipped, or given as outside for
+// walls.
+func (p *PrintDept) forEachNeighbor(x, y int, fn func(idx, weight int)) {
 	halfK := p.KernelSize / 2
 
 	for ky := range p.KernelSize {
 		for kx := range p.KernelSize {
-			if p.Kernel[ky*p.KernelSize+kx] == 0 {
+			weight := p.Kernel[ky*p.KernelSize+kx]
+			if weight == 0 {
 				continue
 			}
 
-			nx := x + kx - halfK
-			ny := y + ky - halfK
+			nx, inX := p.Edge.locate(x+kx-halfK, p.Width)
+			ny, inY := p.Edge.locate(y+ky-halfK, p.Height)
 
-			if nx < 0 || nx >= p.Width || ny < 0 || ny >= p.Height {
-				continue
-			}
-
-			if p.Grid[p.Width*ny+nx] == '@' {
-				count++
+			switch {
+			case inX && inY:
+				fn(p.Width*ny+nx, weight)
+			case p.Edge == EdgeWall:
+				fn(outside, weight)
 			}
 		}
 	}
-
-	return count
 }
 
 func main() {
-	var inputFile string
+	var inputFile, kernelFile, compare, roll, empty, edge, animate, out, heatmap string
+	var reportRun bool
+	var threshold, scale int
+	var delay time.Duration
 
 	flag.StringVar(&inputFile, "input", "day04/input.txt", "input file path")
 	flag.StringVar(&inputFile, "i", "day04/input.txt", "input file path (shorthand)")
+	flag.BoolVar(&reportRun, "report", false, "print a JSON run record for the aoc report")
+	flag.StringVar(&kernelFile, "kernel", "", "file with a square, odd-sized kernel of neighbour weights")
+	flag.IntVar(&threshold, "threshold", 4, "neighbour count a roll is compared against")
+	flag.StringVar(&compare, "cmp", "<", "comparison for a roll to be accessible: <, <=, >, >=, == or !=")
+	flag.StringVar(&roll, "roll", "@", "character for a paper roll")
+	flag.StringVar(&empty, "empty", ".", "character a removed roll leaves")
+	flag.StringVar(&edge, "edge", "empty", "what lies past the grid edge: empty, wall, torus or mirror")
+	flag.StringVar(&animate, "animate", "", "animate the removal waves as gif, png frames or ansi")
+	flag.StringVar(&out, "out", "", "with -animate gif or png, the GIF file or frame directory to write")
+	flag.StringVar(&heatmap, "heatmap", "", "write a PNG of the wave each roll was removed in to this file")
+	flag.IntVar(&scale, "scale", 4, "pixels per grid cell in images")
+	flag.DurationVar(&delay, "delay", 100*time.Millisecond, "time each animation frame is shown")
 	flag.Parse()
 
 	if inputFile == "" {
@@ -169,6 +316,83 @@ func main() {
 	defer f.Close()
 
 	dept := NewPrintDept()
+	dept.Threshold = threshold
+	if dept.Compare, err = ParseComparison(compare); err != nil {
+		log.Fatal(err)
+	}
+	if len(roll) != 1 || len(empty) != 1 || roll == empty {
+		log.Fatal("-roll and -empty must be two different single characters")
+	}
+	dept.Roll, dept.Empty = roll[0], empty[0]
+	if dept.Edge, err = ParseEdge(edge); err != nil {
+		log.Fatal(err)
+	}
+	if kernelFile != "" {
+		kf, err := os.Open(filepath.Clean(kernelFile))
+		if err != nil {
+			log.Fatal(err)
+		}
+		dept.Kernel, dept.KernelSize, err = ParseKernel(kf)
+		kf.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if reportRun {
+		run, err := report.MeasureSteps(4, f, dept.parseInput, dept.solve)
+		if err != nil {
+			log.Fatal(err)
+		}
+		run.Answers = []string{strconv.Itoa(dept.AccessibleRolls), strconv.Itoa(dept.TotalRemoved)}
+		run.SVG = dept.SVG()
+		if err := run.Write(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	dept.Parse(f)
+
+	switch animate {
+	case "":
+	case "gif":
+		err = writeFile(out, func(w io.Writer) error { return dept.WriteGIF(w, scale, delay) })
+	case "png":
+		if out == "" {
+			log.Fatal("-animate png needs -out")
+		}
+		err = dept.WritePNGFrames(filepath.Clean(out), scale)
+	case "ansi":
+		err = dept.WriteANSI(os.Stdout, delay)
+	default:
+		err = fmt.Errorf("unknown animation format %q", animate)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if heatmap != "" {
+		if err := writeFile(heatmap, func(w io.Writer) error { return dept.WriteHeatmap(w, scale) }); err != nil {
+			log.Fatal(err)
+		}
+	}
+
 	fmt.Println(dept)
 }
+
+// writeFile creates the file at path and fills it with write.
+func writeFile(path string, write func(io.Writer) error) error {
+	if path == "" {
+		return fmt.Errorf("no output file specified")
+	}
+	f, err := os.Create(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
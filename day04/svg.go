@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SVG renders the grid after part 2 with each removed roll coloured by the
+// wave it was removed in, from red (first) to blue (last). Rolls that were
+// never removed are drawn in grey.
+func (p *PrintDept) SVG() string {
+	const cell = 4
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d">`,
+		p.Width, p.Height, p.Width*cell, p.Height*cell)
+	fmt.Fprintf(&sb, `<rect width="%d" height="%d" fill="#0f0f23"/>`, p.Width, p.Height)
+
+	// One path per wave keeps the output small on large grids
+	for wave, cells := range p.Waves {
+		hue := 240 * wave / max(len(p.Waves)-1, 1)
+		fmt.Fprintf(&sb, `<path fill="hsl(%d,80%%,55%%)" d="%s"><title>wave %d</title></path>`,
+			hue, cellPath(cells, p.Width), wave+1)
+	}
+
+	var remaining []int
+	for idx, c := range p.Grid {
+		if c == p.Roll {
+			remaining = append(remaining, idx)
+		}
+	}
+	fmt.Fprintf(&sb, `<path fill="#777" d="%s"><title>never removed</title></path>`, cellPath(remaining, p.Width))
+
+	sb.WriteString(`</svg>`)
+	return sb.String()
+}
+
+// cellPath returns SVG path data drawing a unit square for each grid index.
+func cellPath(cells []int, width int) string {
+	var sb strings.Builder
+	for _, idx := range cells {
+		fmt.Fprintf(&sb, "M%d %dh1v1h-1z", idx%width, idx/width)
+	}
+	return sb.String()
+}
@@ -0,0 +1,45 @@
+package main_test
+
+import (
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	main "github.com/lcox74/aoc25/day05"
+	"github.com/lcox74/aoc25/internal/report"
+)
+
+// budget for parsing and solving generatedInput, about 25% over measured.
+var budget = report.Budget{Allocs: 1_800, Bytes: 89_000}
+
+// generatedInput returns 200 fresh ranges followed by 1,000 ingredient IDs.
+func generatedInput() string {
+	rng := rand.New(rand.NewPCG(29, 5))
+
+	var sb strings.Builder
+	for range 200 {
+		start := rng.IntN(1_000_000_000)
+		fmt.Fprintf(&sb, "%d-%d\n", start, start+rng.IntN(10_000_000))
+	}
+	sb.WriteByte('\n')
+	for range 1000 {
+		fmt.Fprintf(&sb, "%d\n", rng.IntN(1_010_000_000))
+	}
+	return sb.String()
+}
+
+func TestAllocBudget(t *testing.T) {
+	budget.Check(t, generatedInput(), func(r io.Reader) {
+		main.NewCafeteria().Parse(r)
+	})
+}
+
+func BenchmarkParse(b *testing.B) {
+	input := generatedInput()
+	b.ReportAllocs()
+	for b.Loop() {
+		main.NewCafeteria().Parse(strings.NewReader(input))
+	}
+}
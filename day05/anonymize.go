@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+)
+
+// Anonymize rewrites the database read from r into an equivalent one on w.
+// Every range bound and ingredient ID is shifted by the same random offset,
+// and both sections are shuffled, which leaves both answers unchanged.
+func Anonymize(r io.Reader, w io.Writer, seed uint64) error {
+	rng := rand.New(rand.NewPCG(seed, seed))
+	shift := rng.IntN(1_000_000_000_000)
+
+	var ranges, ingredients []string
+	parsingRanges := true
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			parsingRanges = false
+			continue
+		}
+
+		if parsingRanges {
+			parts := strings.Split(line, "-")
+			if len(parts) != 2 {
+				continue
+			}
+			start, err1 := strconv.Atoi(parts[0])
+			end, err2 := strconv.Atoi(parts[1])
+			if err1 != nil || err2 != nil {
+				continue
+			}
+			ranges = append(ranges, fmt.Sprintf("%d-%d", start+shift, end+shift))
+		} else {
+			id, err := strconv.Atoi(line)
+			if err != nil {
+				continue
+			}
+			ingredients = append(ingredients, strconv.Itoa(id+shift))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	rng.Shuffle(len(ranges), func(i, j int) { ranges[i], ranges[j] = ranges[j], ranges[i] })
+	rng.Shuffle(len(ingredients), func(i, j int) { ingredients[i], ingredients[j] = ingredients[j], ingredients[i] })
+
+	_, err := fmt.Fprintf(w, "%s\n\n%s\n", strings.Join(ranges, "\n"), strings.Join(ingredients, "\n"))
+	return err
+}
+
+// AnonymizeChecked anonymizes input and verifies the result solves to the
+// same answers before writing it to w.
+func AnonymizeChecked(input []byte, w io.Writer, seed uint64) error {
+	var out bytes.Buffer
+	if err := Anonymize(bytes.NewReader(input), &out, seed); err != nil {
+		return err
+	}
+
+	want, got := NewCafeteria(), NewCafeteria()
+	want.Parse(bytes.NewReader(input))
+	got.Parse(bytes.NewReader(out.Bytes()))
+	if want.FreshCount != got.FreshCount || want.TotalFresh != got.TotalFresh {
+		return fmt.Errorf("anonymized input changed the answers: %d, %d -> %d, %d",
+			want.FreshCount, want.TotalFresh, got.FreshCount, got.TotalFresh)
+	}
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
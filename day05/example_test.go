@@ -1,6 +1,7 @@
 package main_test
 
 import (
+	"bytes"
 	"strings"
 	"testing"
 
@@ -31,3 +32,14 @@ func TestExample(t *testing.T) {
 	// Part 2: 14 unique fresh IDs (3-5, 10-20 merged)
 	require.Equal(t, 14, cafe.TotalFresh)
 }
+
+func TestAnonymize(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, main.AnonymizeChecked([]byte(exampleInput), &out, 42))
+	require.NotEqual(t, exampleInput, out.String())
+
+	cafe := main.NewCafeteria()
+	cafe.Parse(&out)
+	require.Equal(t, 3, cafe.FreshCount)
+	require.Equal(t, 14, cafe.TotalFresh)
+}
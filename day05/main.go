@@ -6,11 +6,14 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"math/rand/v2"
 	"os"
 	"path/filepath"
 	"slices"
 	"strconv"
 	"strings"
+
+	"github.com/lcox74/aoc25/internal/report"
 )
 
 // Cafeteria checks which ingredient IDs are fresh.
@@ -35,6 +38,12 @@ func (c *Cafeteria) String() string {
 // First section contains fresh ID ranges (e.g., "3-5").
 // After a blank line, the second section contains available ingredient IDs.
 func (c *Cafeteria) Parse(r io.Reader) {
+	c.parseInput(r)
+	c.solve()
+}
+
+// parseInput reads the fresh ID ranges and the available ingredients.
+func (c *Cafeteria) parseInput(r io.Reader) {
 	scanner := bufio.NewScanner(r)
 	parsingRanges := true
 
@@ -69,7 +78,10 @@ func (c *Cafeteria) Parse(r io.Reader) {
 			c.Ingredients = append(c.Ingredients, id)
 		}
 	}
+}
 
+// solve counts the fresh ingredients and fresh IDs.
+func (c *Cafeteria) solve() {
 	// Part 1: Count fresh available ingredients
 	for _, id := range c.Ingredients {
 		if c.isFresh(id) {
@@ -129,9 +141,14 @@ func (c *Cafeteria) countTotalFreshIDs() int {
 
 func main() {
 	var inputFile string
+	var reportRun, anonymize bool
+	var seed uint64
 
 	flag.StringVar(&inputFile, "input", "day05/input.txt", "input file path")
 	flag.StringVar(&inputFile, "i", "day05/input.txt", "input file path (shorthand)")
+	flag.BoolVar(&reportRun, "report", false, "print a JSON run record for the aoc report")
+	flag.BoolVar(&anonymize, "anonymize", false, "print an equivalent input with the same answers")
+	flag.Uint64Var(&seed, "seed", 0, "random seed for -anonymize (default random)")
 	flag.Parse()
 
 	if inputFile == "" {
@@ -144,7 +161,33 @@ func main() {
 	}
 	defer f.Close()
 
+	if anonymize {
+		input, err := io.ReadAll(f)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if seed == 0 {
+			seed = rand.Uint64()
+		}
+		if err := AnonymizeChecked(input, os.Stdout, seed); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	cafe := NewCafeteria()
+	if reportRun {
+		run, err := report.MeasureSteps(5, f, cafe.parseInput, cafe.solve)
+		if err != nil {
+			log.Fatal(err)
+		}
+		run.Answers = []string{strconv.Itoa(cafe.FreshCount), strconv.Itoa(cafe.TotalFresh)}
+		if err := run.Write(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	cafe.Parse(f)
 	fmt.Println(cafe)
 }
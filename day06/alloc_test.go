@@ -0,0 +1,48 @@
+package main_test
+
+import (
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	main "github.com/lcox74/aoc25/day06"
+	"github.com/lcox74/aoc25/internal/report"
+)
+
+// budget for parsing and solving generatedInput, about 25% over measured.
+var budget = report.Budget{Allocs: 3_900, Bytes: 150_000}
+
+// generatedInput returns a worksheet of 500 problems with 4 numbers each.
+func generatedInput() string {
+	rng := rand.New(rand.NewPCG(29, 6))
+
+	rows := make([]strings.Builder, 5)
+	for range 500 {
+		for i := range 4 {
+			fmt.Fprintf(&rows[i], "%-4d ", rng.IntN(1000)+1)
+		}
+		fmt.Fprintf(&rows[4], "%-4s ", []string{"+", "*"}[rng.IntN(2)])
+	}
+
+	lines := make([]string, len(rows))
+	for i := range rows {
+		lines[i] = rows[i].String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestAllocBudget(t *testing.T) {
+	budget.Check(t, generatedInput(), func(r io.Reader) {
+		main.NewMathWorksheet().Parse(r)
+	})
+}
+
+func BenchmarkParse(b *testing.B) {
+	input := generatedInput()
+	b.ReportAllocs()
+	for b.Loop() {
+		main.NewMathWorksheet().Parse(strings.NewReader(input))
+	}
+}
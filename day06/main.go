@@ -9,6 +9,8 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+
+	"github.com/lcox74/aoc25/internal/report"
 )
 
 // MathWorksheet solves cephalopod math homework problems.
@@ -124,9 +126,11 @@ func solveVertical(lines []string) int {
 
 func main() {
 	var inputFile string
+	var reportRun bool
 
 	flag.StringVar(&inputFile, "input", "day06/input.txt", "input file path")
 	flag.StringVar(&inputFile, "i", "day06/input.txt", "input file path (shorthand)")
+	flag.BoolVar(&reportRun, "report", false, "print a JSON run record for the aoc report")
 	flag.Parse()
 
 	if inputFile == "" {
@@ -140,6 +144,18 @@ func main() {
 	defer f.Close()
 
 	solver := NewMathWorksheet()
+	if reportRun {
+		run, err := report.Measure(6, f, solver.Parse)
+		if err != nil {
+			log.Fatal(err)
+		}
+		run.Answers = []string{strconv.Itoa(solver.ResultPart1), strconv.Itoa(solver.ResultPart2)}
+		if err := run.Write(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	solver.Parse(f)
 	fmt.Println(solver)
 }
@@ -0,0 +1,49 @@
+package main_test
+
+import (
+	"io"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	main "github.com/lcox74/aoc25/day07"
+	"github.com/lcox74/aoc25/internal/report"
+)
+
+// budget for parsing and solving generatedInput, about 25% over measured.
+var budget = report.Budget{Allocs: 370, Bytes: 42_000}
+
+// generatedInput returns a 141x140 manifold with splitters on every other row.
+func generatedInput() string {
+	rng := rand.New(rand.NewPCG(29, 7))
+
+	var sb strings.Builder
+	for row := range 140 {
+		for col := range 141 {
+			switch {
+			case row == 0 && col == 70:
+				sb.WriteByte('S')
+			case row%2 == 0 && row > 0 && rng.IntN(4) == 0:
+				sb.WriteByte('^')
+			default:
+				sb.WriteByte('.')
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+func TestAllocBudget(t *testing.T) {
+	budget.Check(t, generatedInput(), func(r io.Reader) {
+		main.NewTachyonManifold().Parse(r)
+	})
+}
+
+func BenchmarkParse(b *testing.B) {
+	input := generatedInput()
+	b.ReportAllocs()
+	for b.Loop() {
+		main.NewTachyonManifold().Parse(strings.NewReader(input))
+	}
+}
@@ -8,6 +8,9 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strconv"
+
+	"github.com/lcox74/aoc25/internal/report"
 )
 
 // TachyonManifold simulates tachyon beams passing through a manifold with splitters.
@@ -35,6 +38,12 @@ func (t *TachyonManifold) String() string {
 
 // Parse reads the manifold diagram from an io.Reader.
 func (t *TachyonManifold) Parse(r io.Reader) {
+	t.parseInput(r)
+	t.solve()
+}
+
+// parseInput reads the rows of splitters as bitmasks.
+func (t *TachyonManifold) parseInput(r io.Reader) {
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -62,7 +71,6 @@ func (t *TachyonManifold) Parse(r io.Reader) {
 			t.splitters = append(t.splitters, rowMask)
 		}
 	}
-	t.solve()
 }
 
 // solve simulates beams through the manifold, computing both parts in one pass.
@@ -119,9 +127,11 @@ func sum(vals []int) int {
 
 func main() {
 	var inputFile string
+	var reportRun bool
 
 	flag.StringVar(&inputFile, "input", "day07/input.txt", "input file path")
 	flag.StringVar(&inputFile, "i", "day07/input.txt", "input file path (shorthand)")
+	flag.BoolVar(&reportRun, "report", false, "print a JSON run record for the aoc report")
 	flag.Parse()
 
 	if inputFile == "" {
@@ -135,6 +145,19 @@ func main() {
 	defer f.Close()
 
 	solver := NewTachyonManifold()
+	if reportRun {
+		run, err := report.MeasureSteps(7, f, solver.parseInput, solver.solve)
+		if err != nil {
+			log.Fatal(err)
+		}
+		run.Answers = []string{strconv.Itoa(solver.ResultPart1), strconv.Itoa(solver.ResultPart2)}
+		run.SVG = solver.SVG()
+		if err := run.Write(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	solver.Parse(f)
 	fmt.Println(solver)
 }
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// beamLevels is the number of opacity steps used to shade beams by timeline count.
+const beamLevels = 8
+
+// SVG renders the beam paths through the manifold. Only rows containing
+// splitters are drawn; beams get more opaque the more timelines they carry.
+func (t *TachyonManifold) SVG() string {
+	rows := len(t.splitters)
+	paths := make([]strings.Builder, beamLevels)
+	var splitters strings.Builder
+
+	maxLog := math.Log1p(float64(max(t.ResultPart2, 1)))
+	level := func(count int) int {
+		return min(int(math.Log1p(float64(count))/maxLog*beamLevels), beamLevels-1)
+	}
+
+	timelines := make([]int, t.width)
+	next := make([]int, t.width)
+	timelines[t.startCol] = 1
+
+	for row, splitterMask := range t.splitters {
+		clear(next)
+		y := float64(2 * row)
+
+		for col, count := range timelines {
+			if t.hasSplitter(splitterMask, col) {
+				fmt.Fprintf(&splitters, "M%g %gl-0.5 1h1z", float64(col)+0.5, y+1)
+			}
+			if count == 0 {
+				continue
+			}
+
+			x := float64(col) + 0.5
+			path := &paths[level(count)]
+			if !t.hasSplitter(splitterMask, col) {
+				fmt.Fprintf(path, "M%g %gv2", x, y)
+				next[col] += count
+				continue
+			}
+
+			fmt.Fprintf(path, "M%g %gv1", x, y)
+			if col > 0 {
+				fmt.Fprintf(path, "M%g %gl-1 1", x, y+1)
+				next[col-1] += count
+			}
+			if col+1 < t.width {
+				fmt.Fprintf(path, "M%g %gl1 1", x, y+1)
+				next[col+1] += count
+			}
+		}
+		timelines, next = next, timelines
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d">`,
+		t.width, 2*rows, t.width*6, rows*12)
+	fmt.Fprintf(&sb, `<rect width="%d" height="%d" fill="#0f0f23"/>`, t.width, 2*rows)
+	for i := range paths {
+		if paths[i].Len() == 0 {
+			continue
+		}
+		opacity := float64(i+1) / beamLevels
+		fmt.Fprintf(&sb, `<path fill="none" stroke="#ffff66" stroke-width="0.3" stroke-opacity="%.3f" d="%s"/>`,
+			opacity, paths[i].String())
+	}
+	fmt.Fprintf(&sb, `<path fill="#00cc00" d="%s"/>`, splitters.String())
+	sb.WriteString(`</svg>`)
+
+	return sb.String()
+}
@@ -0,0 +1,40 @@
+package main_test
+
+import (
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	main "github.com/lcox74/aoc25/day08"
+	"github.com/lcox74/aoc25/internal/report"
+)
+
+// budget for parsing and solving generatedInput, about 25% over measured.
+var budget = report.Budget{Allocs: 1_300, Bytes: 3_900_000}
+
+// generatedInput returns 500 junction boxes in a 100,000 unit cube.
+func generatedInput() string {
+	rng := rand.New(rand.NewPCG(29, 8))
+
+	var sb strings.Builder
+	for range 500 {
+		fmt.Fprintf(&sb, "%d,%d,%d\n", rng.IntN(100_000), rng.IntN(100_000), rng.IntN(100_000))
+	}
+	return sb.String()
+}
+
+func TestAllocBudget(t *testing.T) {
+	budget.Check(t, generatedInput(), func(r io.Reader) {
+		main.NewPlayground().Parse(r)
+	})
+}
+
+func BenchmarkParse(b *testing.B) {
+	input := generatedInput()
+	b.ReportAllocs()
+	for b.Loop() {
+		main.NewPlayground().Parse(strings.NewReader(input))
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+)
+
+// Anonymize rewrites the junction boxes read from r into an equivalent set
+// on w. Distances are kept by translating, mirroring and swapping the Y and
+// Z axes; X is left alone because part 2 multiplies X coordinates. Boxes are
+// also shuffled.
+func Anonymize(r io.Reader, w io.Writer, seed uint64) error {
+	rng := rand.New(rand.NewPCG(seed, seed))
+
+	var boxes []JunctionBox
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		parts := strings.Split(scanner.Text(), ",")
+		if len(parts) != 3 {
+			continue
+		}
+		x, err1 := strconv.Atoi(parts[0])
+		y, err2 := strconv.Atoi(parts[1])
+		z, err3 := strconv.Atoi(parts[2])
+		if err1 != nil || err2 != nil || err3 != nil {
+			continue
+		}
+		boxes = append(boxes, JunctionBox{X: x, Y: y, Z: z})
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	swap := rng.IntN(2) == 0
+	mirrorY, mirrorZ := rng.IntN(2) == 0, rng.IntN(2) == 0
+	offsetY, offsetZ := rng.IntN(100_000), rng.IntN(100_000)
+
+	// Mirroring subtracts from the largest coordinate so values stay non-negative
+	maxY, maxZ := 0, 0
+	for _, b := range boxes {
+		maxY, maxZ = max(maxY, b.Y), max(maxZ, b.Z)
+	}
+
+	for i, b := range boxes {
+		if mirrorY {
+			b.Y = maxY - b.Y
+		}
+		if mirrorZ {
+			b.Z = maxZ - b.Z
+		}
+		b.Y += offsetY
+		b.Z += offsetZ
+		if swap {
+			b.Y, b.Z = b.Z, b.Y
+		}
+		boxes[i] = b
+	}
+
+	rng.Shuffle(len(boxes), func(i, j int) { boxes[i], boxes[j] = boxes[j], boxes[i] })
+
+	bw := bufio.NewWriter(w)
+	for _, b := range boxes {
+		fmt.Fprintf(bw, "%d,%d,%d\n", b.X, b.Y, b.Z)
+	}
+	return bw.Flush()
+}
+
+// AnonymizeChecked anonymizes input and verifies the result solves to the
+// same answers before writing it to w.
+func AnonymizeChecked(input []byte, w io.Writer, seed uint64) error {
+	var out bytes.Buffer
+	if err := Anonymize(bytes.NewReader(input), &out, seed); err != nil {
+		return err
+	}
+
+	want, got := NewPlayground(), NewPlayground()
+	want.Parse(bytes.NewReader(input))
+	got.Parse(bytes.NewReader(out.Bytes()))
+	if want.ResultPart1 != got.ResultPart1 || want.ResultPart2 != got.ResultPart2 {
+		return fmt.Errorf("anonymized input changed the answers: %d, %d -> %d, %d",
+			want.ResultPart1, want.ResultPart2, got.ResultPart1, got.ResultPart2)
+	}
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
@@ -1,6 +1,7 @@
 package main_test
 
 import (
+	"bytes"
 	"strings"
 	"testing"
 
@@ -46,3 +47,15 @@ func TestExample(t *testing.T) {
 	// 216,146,977 and 117,168,530 -> 216 * 117 = 25272
 	require.Equal(t, 25272, solver.ResultPart2)
 }
+
+func TestAnonymize(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, main.AnonymizeChecked([]byte(exampleInput), &out, 42))
+	require.NotEqual(t, exampleInput, out.String())
+
+	solver := main.NewPlayground()
+	solver.Parse(&out)
+	solver.Solve(10)
+	require.Equal(t, 40, solver.ResultPart1)
+	require.Equal(t, 25272, solver.ResultPart2)
+}
@@ -6,10 +6,13 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"math/rand/v2"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+
+	"github.com/lcox74/aoc25/internal/report"
 )
 
 // JunctionBox represents a junction box position in 3D space.
@@ -41,6 +44,12 @@ func (p *Playground) String() string {
 
 // Parse reads junction box coordinates from an io.Reader.
 func (p *Playground) Parse(r io.Reader) {
+	p.parseInput(r)
+	p.Solve(1000)
+}
+
+// parseInput reads the junction box positions.
+func (p *Playground) parseInput(r io.Reader) {
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -59,7 +68,6 @@ func (p *Playground) Parse(r io.Reader) {
 		}
 		p.boxes = append(p.boxes, JunctionBox{X: x, Y: y, Z: z})
 	}
-	p.Solve(1000)
 }
 
 // Solve connects junction boxes using Kruskal's MST algorithm.
@@ -95,9 +103,14 @@ func (p *Playground) Solve(numConnections int) {
 
 func main() {
 	var inputFile string
+	var reportRun, anonymize bool
+	var seed uint64
 
 	flag.StringVar(&inputFile, "input", "day08/input.txt", "input file path")
 	flag.StringVar(&inputFile, "i", "day08/input.txt", "input file path (shorthand)")
+	flag.BoolVar(&reportRun, "report", false, "print a JSON run record for the aoc report")
+	flag.BoolVar(&anonymize, "anonymize", false, "print an equivalent input with the same answers")
+	flag.Uint64Var(&seed, "seed", 0, "random seed for -anonymize (default random)")
 	flag.Parse()
 
 	if inputFile == "" {
@@ -110,7 +123,33 @@ func main() {
 	}
 	defer f.Close()
 
+	if anonymize {
+		input, err := io.ReadAll(f)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if seed == 0 {
+			seed = rand.Uint64()
+		}
+		if err := AnonymizeChecked(input, os.Stdout, seed); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	solver := NewPlayground()
+	if reportRun {
+		run, err := report.MeasureSteps(8, f, solver.parseInput, func() { solver.Solve(1000) })
+		if err != nil {
+			log.Fatal(err)
+		}
+		run.Answers = []string{strconv.Itoa(solver.ResultPart1), strconv.Itoa(solver.ResultPart2)}
+		if err := run.Write(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	solver.Parse(f)
 	fmt.Println(solver)
 }
@@ -0,0 +1,51 @@
+package main_test
+
+import (
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	main "github.com/lcox74/aoc25/day09"
+	"github.com/lcox74/aoc25/internal/report"
+)
+
+// budget for parsing and solving generatedInput, about 25% over measured.
+var budget = report.Budget{Allocs: 1_400, Bytes: 240_000}
+
+// generatedInput returns the 202 red tiles of a histogram-shaped polygon
+// made of 100 columns with random, distinct neighbouring heights.
+func generatedInput() string {
+	rng := rand.New(rand.NewPCG(29, 9))
+
+	var sb strings.Builder
+	x, prev := 0, 0
+	fmt.Fprintf(&sb, "%d,%d\n", x, 0)
+	for range 100 {
+		h := prev
+		for h == prev {
+			h = rng.IntN(10_000) + 1
+		}
+		fmt.Fprintf(&sb, "%d,%d\n", x, h)
+		x += rng.IntN(1000) + 1
+		fmt.Fprintf(&sb, "%d,%d\n", x, h)
+		prev = h
+	}
+	fmt.Fprintf(&sb, "%d,%d\n", x, 0)
+	return sb.String()
+}
+
+func TestAllocBudget(t *testing.T) {
+	budget.Check(t, generatedInput(), func(r io.Reader) {
+		main.NewMovieTheater().Parse(r)
+	})
+}
+
+func BenchmarkParse(b *testing.B) {
+	input := generatedInput()
+	b.ReportAllocs()
+	for b.Loop() {
+		main.NewMovieTheater().Parse(strings.NewReader(input))
+	}
+}
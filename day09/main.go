@@ -10,6 +10,8 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+
+	"github.com/lcox74/aoc25/internal/report"
 )
 
 // MovieTheater finds the largest rectangle using red tiles as opposite corners.
@@ -20,6 +22,8 @@ type MovieTheater struct {
 	TilesY      []int // Y coordinates of red tiles
 	ResultPart1 int   // Maximum rectangle area (any two red tiles)
 	ResultPart2 int   // Maximum rectangle area (only red/green tiles)
+
+	bestRect [4]int // xMin, yMin, xMax, yMax of the part 2 rectangle
 }
 
 func NewMovieTheater() *MovieTheater {
@@ -32,6 +36,12 @@ func (m *MovieTheater) String() string {
 
 // Parse reads coordinate pairs from r and finds the maximum rectangle area.
 func (m *MovieTheater) Parse(r io.Reader) {
+	m.parseInput(r)
+	m.solve()
+}
+
+// parseInput reads the red tile positions.
+func (m *MovieTheater) parseInput(r io.Reader) {
 	scanner := bufio.NewScanner(r)
 
 	for scanner.Scan() {
@@ -58,8 +68,6 @@ func (m *MovieTheater) Parse(r io.Reader) {
 		m.TilesX = append(m.TilesX, x)
 		m.TilesY = append(m.TilesY, y)
 	}
-
-	m.solve()
 }
 
 // solve finds the maximum rectangle areas for both parts.
@@ -86,8 +94,9 @@ func (m *MovieTheater) solve() {
 			m.ResultPart1 = max(m.ResultPart1, area)
 
 			// Part 2: Only rectangles inside polygon
-			if isInsidePolygon(outside, xIdx, yIdx, xMin, xMax, yMin, yMax) {
-				m.ResultPart2 = max(m.ResultPart2, area)
+			if area > m.ResultPart2 && isInsidePolygon(outside, xIdx, yIdx, xMin, xMax, yMin, yMax) {
+				m.ResultPart2 = area
+				m.bestRect = [4]int{xMin, yMin, xMax, yMax}
 			}
 		}
 	}
@@ -95,9 +104,11 @@ func (m *MovieTheater) solve() {
 
 func main() {
 	var inputFile string
+	var reportRun bool
 
 	flag.StringVar(&inputFile, "input", "day09/input.txt", "input file path")
 	flag.StringVar(&inputFile, "i", "day09/input.txt", "input file path (shorthand)")
+	flag.BoolVar(&reportRun, "report", false, "print a JSON run record for the aoc report")
 	flag.Parse()
 
 	if inputFile == "" {
@@ -111,6 +122,19 @@ func main() {
 	defer f.Close()
 
 	theater := NewMovieTheater()
+	if reportRun {
+		run, err := report.MeasureSteps(9, f, theater.parseInput, theater.solve)
+		if err != nil {
+			log.Fatal(err)
+		}
+		run.Answers = []string{strconv.Itoa(theater.ResultPart1), strconv.Itoa(theater.ResultPart2)}
+		run.SVG = theater.SVG()
+		if err := run.Write(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	theater.Parse(f)
 	fmt.Println(theater)
 }
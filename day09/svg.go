@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SVG renders the polygon of red tiles with the largest part 2 rectangle
+// drawn on top of it.
+func (m *MovieTheater) SVG() string {
+	if len(m.TilesX) == 0 {
+		return ""
+	}
+
+	minX, maxX := m.TilesX[0], m.TilesX[0]
+	minY, maxY := m.TilesY[0], m.TilesY[0]
+	for i := range m.TilesX {
+		minX, maxX = min(minX, m.TilesX[i]), max(maxX, m.TilesX[i])
+		minY, maxY = min(minY, m.TilesY[i]), max(maxY, m.TilesY[i])
+	}
+	w, h := maxX-minX+1, maxY-minY+1
+	stroke := float64(max(w, h)) / 400
+
+	var points strings.Builder
+	for i := range m.TilesX {
+		fmt.Fprintf(&points, "%d,%d ", m.TilesX[i], m.TilesY[i])
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="%d %d %d %d" width="600" height="%d">`,
+		minX, minY, w, h, 600*h/w)
+	fmt.Fprintf(&sb, `<rect x="%d" y="%d" width="%d" height="%d" fill="#0f0f23"/>`, minX, minY, w, h)
+	fmt.Fprintf(&sb, `<polygon fill="#005500" stroke="#cc0000" stroke-width="%g" points="%s"/>`,
+		stroke, strings.TrimSpace(points.String()))
+
+	if m.ResultPart2 > 0 {
+		r := m.bestRect
+		fmt.Fprintf(&sb,
+			`<rect x="%d" y="%d" width="%d" height="%d" fill="#ffff66" fill-opacity="0.4" stroke="#ffff66" stroke-width="%g"/>`,
+			r[0], r[1], r[2]-r[0]+1, r[3]-r[1]+1, stroke)
+	}
+	sb.WriteString(`</svg>`)
+
+	return sb.String()
+}
@@ -0,0 +1,71 @@
+package main_test
+
+import (
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	main "github.com/lcox74/aoc25/day10"
+	"github.com/lcox74/aoc25/internal/report"
+)
+
+// budget for parsing and solving generatedInput, about 25% over measured.
+// The peak varies with when the collector runs, so it's over the most seen.
+var budget = report.Budget{Allocs: 145_000, Bytes: 11_600_000, Peak: 4_400_000}
+
+// generatedInput returns 2000 machines with 8 lights and 6 buttons, whose
+// targets come from pressing each button a random number of times. Solving
+// them leaves enough garbage for the collector to run.
+func generatedInput() string {
+	rng := rand.New(rand.NewPCG(29, 10))
+
+	var sb strings.Builder
+	for range 2000 {
+		const lights, buttons = 8, 6
+		joltage := make([]int, lights)
+		sb.WriteString("[")
+		var btnText strings.Builder
+		for range buttons {
+			var idx []string
+			presses := rng.IntN(20)
+			for light := range lights {
+				if rng.IntN(3) == 0 {
+					idx = append(idx, fmt.Sprint(light))
+					joltage[light] += presses
+				}
+			}
+			if len(idx) == 0 {
+				idx = append(idx, "0")
+				joltage[0] += presses
+			}
+			fmt.Fprintf(&btnText, " (%s)", strings.Join(idx, ","))
+		}
+		for _, j := range joltage {
+			sb.WriteByte(".#"[j%2])
+		}
+		sb.WriteString("]")
+		sb.WriteString(btnText.String())
+		levels := make([]string, lights)
+		for i, j := range joltage {
+			levels[i] = fmt.Sprint(j)
+		}
+		fmt.Fprintf(&sb, " {%s}\n", strings.Join(levels, ","))
+	}
+	return sb.String()
+}
+
+func TestAllocBudget(t *testing.T) {
+	budget.Check(t, generatedInput(), func(r io.Reader) {
+		main.NewFactory().Parse(r)
+	})
+}
+
+func BenchmarkParse(b *testing.B) {
+	input := generatedInput()
+	b.ReportAllocs()
+	for b.Loop() {
+		main.NewFactory().Parse(strings.NewReader(input))
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Anonymize rewrites the machines read from r into equivalent ones on w.
+// Each machine has its lights relabelled (pattern, button wiring and joltage
+// targets alike) and its buttons reordered, and the machines are shuffled.
+// Part 1's minimum doesn't depend on any of these, but part 2's bounded
+// search over free variables can land on a different count for some orders,
+// so use AnonymizeChecked to be sure the answers are kept.
+func Anonymize(r io.Reader, w io.Writer, seed uint64) error {
+	rng := rand.New(rand.NewPCG(seed, seed))
+
+	var machines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		pm := patternRe.FindStringSubmatch(line)
+		if pm == nil {
+			continue
+		}
+
+		var joltages []int
+		if jm := joltageRe.FindStringSubmatch(line); jm != nil {
+			joltages = parseInts(jm[1])
+		}
+		buttons := parseButtons(buttonRe.FindAllStringSubmatch(line, -1))
+
+		machines = append(machines, anonymizeMachine(rng, pm[1], buttons, joltages))
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	rng.Shuffle(len(machines), func(i, j int) { machines[i], machines[j] = machines[j], machines[i] })
+
+	bw := bufio.NewWriter(w)
+	for _, m := range machines {
+		bw.WriteString(m)
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// anonymizeMachine relabels the lights of one machine with a random
+// permutation, shuffles its buttons and formats it as an input line.
+func anonymizeMachine(rng *rand.Rand, pattern string, buttons [][]int, joltages []int) string {
+	n := len(pattern)
+	perm := rng.Perm(n)
+	if joltages != nil && len(joltages) != n {
+		// Lights and counters disagree, so only the buttons are shuffled
+		for i := range perm {
+			perm[i] = i
+		}
+	}
+	relabel := func(i int) int {
+		if i < n {
+			return perm[i]
+		}
+		return i
+	}
+
+	newPattern := make([]byte, n)
+	for i := range n {
+		newPattern[perm[i]] = pattern[i]
+	}
+
+	newButtons := make([]string, len(buttons))
+	for b, btn := range buttons {
+		idx := make([]int, len(btn))
+		for k, i := range btn {
+			idx[k] = relabel(i)
+		}
+		slices.Sort(idx)
+		newButtons[b] = "(" + joinInts(idx) + ")"
+	}
+	rng.Shuffle(len(newButtons), func(i, j int) { newButtons[i], newButtons[j] = newButtons[j], newButtons[i] })
+
+	line := fmt.Sprintf("[%s] %s", newPattern, strings.Join(newButtons, " "))
+	if joltages == nil {
+		return line
+	}
+
+	newJoltages := make([]int, len(joltages))
+	for i, j := range joltages {
+		newJoltages[relabel(i)] = j
+	}
+	return line + " {" + joinInts(newJoltages) + "}"
+}
+
+// joinInts formats numbers as a comma separated list.
+func joinInts(nums []int) string {
+	parts := make([]string, len(nums))
+	for i, v := range nums {
+		parts[i] = strconv.Itoa(v)
+	}
+	return strings.Join(parts, ",")
+}
+
+// ErrAnswersChanged is returned by AnonymizeChecked when the anonymized
+// input solves to different answers than the original.
+var ErrAnswersChanged = errors.New("anonymized input changed the answers")
+
+// AnonymizeChecked anonymizes input and verifies the result solves to the
+// same answers before writing it to w.
+func AnonymizeChecked(input []byte, w io.Writer, seed uint64) error {
+	var out bytes.Buffer
+	if err := Anonymize(bytes.NewReader(input), &out, seed); err != nil {
+		return err
+	}
+
+	want, got := NewFactory(), NewFactory()
+	want.Parse(bytes.NewReader(input))
+	got.Parse(bytes.NewReader(out.Bytes()))
+	if want.ResultPart1 != got.ResultPart1 || want.ResultPart2 != got.ResultPart2 {
+		return fmt.Errorf("%w: %d, %d -> %d, %d", ErrAnswersChanged,
+			want.ResultPart1, want.ResultPart2, got.ResultPart1, got.ResultPart2)
+	}
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// AnonymizeRandom tries up to attempts random seeds with AnonymizeChecked
+// until one keeps the answers, and returns the seed it used.
+func AnonymizeRandom(input []byte, w io.Writer, attempts int) (uint64, error) {
+	var err error
+	for range attempts {
+		seed := rand.Uint64()
+		if err = AnonymizeChecked(input, w, seed); !errors.Is(err, ErrAnswersChanged) {
+			return seed, err
+		}
+	}
+	return 0, err
+}
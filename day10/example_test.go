@@ -1,6 +1,8 @@
 package main_test
 
 import (
+	"bytes"
+	"os"
 	"strings"
 	"testing"
 
@@ -31,3 +33,43 @@ func TestExample(t *testing.T) {
 	// Total: 10 + 12 + 11 = 33
 	require.Equal(t, 33, factory.ResultPart2)
 }
+
+func TestAnonymize(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, main.AnonymizeChecked([]byte(exampleInput), &out, 42))
+	require.NotEqual(t, exampleInput, out.String())
+
+	factory := main.NewFactory()
+	factory.Parse(&out)
+	require.Equal(t, 7, factory.ResultPart1)
+	require.Equal(t, 33, factory.ResultPart2)
+}
+
+func TestAnonymizeSeeds(t *testing.T) {
+	if testing.Short() {
+		t.Skip("solves the full input several times")
+	}
+	input, err := os.ReadFile("input.txt")
+	require.NoError(t, err)
+
+	// Part 2 can change for some shuffles, and those seeds must say so
+	// rather than write an input with different answers
+	kept := 0
+	for seed := range uint64(4) {
+		var out bytes.Buffer
+		err := main.AnonymizeChecked(input, &out, seed+1)
+		if err != nil {
+			require.ErrorIs(t, err, main.ErrAnswersChanged, "seed %d", seed+1)
+			require.Zero(t, out.Len(), "seed %d", seed+1)
+			continue
+		}
+		require.NotEqual(t, string(input), out.String())
+		kept++
+	}
+	require.Positive(t, kept)
+
+	var out bytes.Buffer
+	_, err = main.AnonymizeRandom(input, &out, 10)
+	require.NoError(t, err)
+	require.NotZero(t, out.Len())
+}
@@ -11,6 +11,14 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/lcox74/aoc25/internal/report"
+)
+
+var (
+	patternRe = regexp.MustCompile(`\[([.#]+)\]`)
+	buttonRe  = regexp.MustCompile(`\(([0-9,]*)\)`)
+	joltageRe = regexp.MustCompile(`\{([0-9,]+)\}`)
 )
 
 type Factory struct {
@@ -26,10 +34,6 @@ func (f *Factory) String() string {
 
 func (f *Factory) Parse(r io.Reader) {
 	scanner := bufio.NewScanner(r)
-	patternRe := regexp.MustCompile(`\[([.#]+)\]`)
-	buttonRe := regexp.MustCompile(`\(([0-9,]*)\)`)
-	joltageRe := regexp.MustCompile(`\{([0-9,]+)\}`)
-
 	for scanner.Scan() {
 		line := scanner.Text()
 		if line == "" {
@@ -146,8 +150,13 @@ func solveAdd(joltages []int, buttons [][]int) int {
 
 func main() {
 	var inputFile string
+	var reportRun, anonymize bool
+	var seed uint64
 	flag.StringVar(&inputFile, "input", "day10/input.txt", "input file path")
 	flag.StringVar(&inputFile, "i", "day10/input.txt", "input file path (shorthand)")
+	flag.BoolVar(&reportRun, "report", false, "print a JSON run record for the aoc report")
+	flag.BoolVar(&anonymize, "anonymize", false, "print an equivalent input with the same answers")
+	flag.Uint64Var(&seed, "seed", 0, "random seed for -anonymize (default random)")
 	flag.Parse()
 
 	if inputFile == "" {
@@ -160,7 +169,36 @@ func main() {
 	}
 	defer f.Close()
 
+	if anonymize {
+		input, err := io.ReadAll(f)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if seed == 0 {
+			// Only a fixed seed is held to the first shuffle
+			_, err = AnonymizeRandom(input, os.Stdout, 10)
+		} else {
+			err = AnonymizeChecked(input, os.Stdout, seed)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	factory := NewFactory()
+	if reportRun {
+		run, err := report.Measure(10, f, factory.Parse)
+		if err != nil {
+			log.Fatal(err)
+		}
+		run.Answers = []string{strconv.Itoa(factory.ResultPart1), strconv.Itoa(factory.ResultPart2)}
+		if err := run.Write(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	factory.Parse(f)
 	fmt.Println(factory)
 }
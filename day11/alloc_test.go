@@ -0,0 +1,57 @@
+package main_test
+
+import (
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	main "github.com/lcox74/aoc25/day11"
+	"github.com/lcox74/aoc25/internal/report"
+)
+
+// budget for parsing and solving generatedInput, about 25% over measured.
+var budget = report.Budget{Allocs: 2_200, Bytes: 280_000}
+
+// generatedInput returns a 500 device DAG where each device feeds up to
+// three of the next 20 devices, with svr, you, fft and dac spread along it.
+func generatedInput() string {
+	rng := rand.New(rand.NewPCG(29, 11))
+
+	const n = 500
+	names := make([]string, n)
+	for i := range names {
+		names[i] = fmt.Sprintf("d%03d", i)
+	}
+	names[0], names[50], names[150], names[350] = "svr", "you", "fft", "dac"
+
+	var sb strings.Builder
+	for i := range n {
+		fmt.Fprintf(&sb, "%s:", names[i])
+		for range rng.IntN(3) + 1 {
+			j := i + rng.IntN(20) + 1
+			if j >= n {
+				sb.WriteString(" out")
+			} else {
+				fmt.Fprintf(&sb, " %s", names[j])
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+func TestAllocBudget(t *testing.T) {
+	budget.Check(t, generatedInput(), func(r io.Reader) {
+		main.NewReactor().Parse(r)
+	})
+}
+
+func BenchmarkParse(b *testing.B) {
+	input := generatedInput()
+	b.ReportAllocs()
+	for b.Loop() {
+		main.NewReactor().Parse(strings.NewReader(input))
+	}
+}
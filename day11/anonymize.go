@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"strings"
+)
+
+// Anonymize rewrites the device list read from r into an equivalent one on
+// w. Every device except the ones the puzzle refers to (you, svr, dac, fft
+// and out) gets a random three letter name, and the lines and the outputs on
+// each line are shuffled. Path counts don't depend on names or order.
+func Anonymize(r io.Reader, w io.Writer, seed uint64) error {
+	rng := rand.New(rand.NewPCG(seed, seed))
+
+	type device struct {
+		name    string
+		targets []string
+	}
+
+	var devices []device
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), ": ", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		devices = append(devices, device{parts[0], strings.Fields(parts[1])})
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	names := map[string]string{"you": "you", "svr": "svr", "dac": "dac", "fft": "fft", "out": "out"}
+	used := make(map[string]bool)
+	for _, n := range names {
+		used[n] = true
+	}
+	rename := func(name string) string {
+		if n, ok := names[name]; ok {
+			return n
+		}
+		n := randomName(rng)
+		for used[n] {
+			n = randomName(rng)
+		}
+		used[n] = true
+		names[name] = n
+		return n
+	}
+
+	lines := make([]string, len(devices))
+	for i, d := range devices {
+		targets := make([]string, len(d.targets))
+		for j, t := range d.targets {
+			targets[j] = rename(t)
+		}
+		rng.Shuffle(len(targets), func(a, b int) { targets[a], targets[b] = targets[b], targets[a] })
+		lines[i] = rename(d.name) + ": " + strings.Join(targets, " ")
+	}
+	rng.Shuffle(len(lines), func(i, j int) { lines[i], lines[j] = lines[j], lines[i] })
+
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+// randomName returns a random three letter lowercase device name.
+func randomName(rng *rand.Rand) string {
+	b := make([]byte, 3)
+	for i := range b {
+		b[i] = byte('a' + rng.IntN(26))
+	}
+	return string(b)
+}
+
+// AnonymizeChecked anonymizes input and verifies the result solves to the
+// same answers before writing it to w.
+func AnonymizeChecked(input []byte, w io.Writer, seed uint64) error {
+	var out bytes.Buffer
+	if err := Anonymize(bytes.NewReader(input), &out, seed); err != nil {
+		return err
+	}
+
+	want, got := NewReactor(), NewReactor()
+	want.Parse(bytes.NewReader(input))
+	got.Parse(bytes.NewReader(out.Bytes()))
+	if want.ResultPart1 != got.ResultPart1 || want.ResultPart2 != got.ResultPart2 {
+		return fmt.Errorf("anonymized input changed the answers: %d, %d -> %d, %d",
+			want.ResultPart1, want.ResultPart2, got.ResultPart1, got.ResultPart2)
+	}
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
@@ -1,6 +1,7 @@
 package main_test
 
 import (
+	"bytes"
 	"strings"
 	"testing"
 
@@ -59,3 +60,26 @@ func TestExamplePart2(t *testing.T) {
 	// svr -> aaa -> fft -> ccc -> eee -> dac -> fff -> hhh -> out
 	require.Equal(t, 2, reactor.ResultPart2)
 }
+
+func TestAnonymize(t *testing.T) {
+	for _, input := range []string{exampleInput, exampleInputPart2} {
+		var out bytes.Buffer
+		require.NoError(t, main.AnonymizeChecked([]byte(input), &out, 42))
+		require.NotEqual(t, input, out.String())
+
+		want, got := main.NewReactor(), main.NewReactor()
+		want.Parse(strings.NewReader(input))
+		got.Parse(&out)
+		require.Equal(t, want.ResultPart1, got.ResultPart1)
+		require.Equal(t, want.ResultPart2, got.ResultPart2)
+	}
+}
+
+func TestSVGEscapesNames(t *testing.T) {
+	reactor := main.NewReactor()
+	reactor.Parse(strings.NewReader("you: a<b&c\na<b&c: out\n"))
+
+	svg := reactor.SVG()
+	require.Contains(t, svg, "<title>a&lt;b&amp;c</title>")
+	require.NotContains(t, svg, "a<b")
+}
@@ -6,9 +6,13 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"math/rand/v2"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+
+	"github.com/lcox74/aoc25/internal/report"
 )
 
 // Reactor solves device path counting puzzles.
@@ -31,6 +35,12 @@ func (r *Reactor) String() string {
 }
 
 func (r *Reactor) Parse(rd io.Reader) {
+	r.parseInput(rd)
+	r.solve()
+}
+
+// parseInput reads the outputs of each device.
+func (r *Reactor) parseInput(rd io.Reader) {
 	scanner := bufio.NewScanner(rd)
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -47,7 +57,10 @@ func (r *Reactor) Parse(rd io.Reader) {
 		targets := strings.Fields(parts[1])
 		r.graph[device] = targets
 	}
+}
 
+// solve counts the paths for both parts.
+func (r *Reactor) solve() {
 	r.ResultPart1 = r.countPaths("you", make(map[string]int))
 	r.ResultPart2 = r.countPathsWithCheckpoints("svr", false, false, make(map[string]int))
 }
@@ -105,8 +118,13 @@ func (r *Reactor) countPathsWithCheckpoints(current string, visitedDac, visitedF
 
 func main() {
 	var inputFile string
+	var reportRun, anonymize bool
+	var seed uint64
 	flag.StringVar(&inputFile, "input", "day11/input.txt", "input file path")
 	flag.StringVar(&inputFile, "i", "day11/input.txt", "input file path (shorthand)")
+	flag.BoolVar(&reportRun, "report", false, "print a JSON run record for the aoc report")
+	flag.BoolVar(&anonymize, "anonymize", false, "print an equivalent input with the same answers")
+	flag.Uint64Var(&seed, "seed", 0, "random seed for -anonymize (default random)")
 	flag.Parse()
 
 	if inputFile == "" {
@@ -119,7 +137,34 @@ func main() {
 	}
 	defer f.Close()
 
+	if anonymize {
+		input, err := io.ReadAll(f)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if seed == 0 {
+			seed = rand.Uint64()
+		}
+		if err := AnonymizeChecked(input, os.Stdout, seed); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	reactor := NewReactor()
+	if reportRun {
+		run, err := report.MeasureSteps(11, f, reactor.parseInput, reactor.solve)
+		if err != nil {
+			log.Fatal(err)
+		}
+		run.Answers = []string{strconv.Itoa(reactor.ResultPart1), strconv.Itoa(reactor.ResultPart2)}
+		run.SVG = reactor.SVG()
+		if err := run.Write(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	reactor.Parse(f)
 	fmt.Println(reactor)
 }
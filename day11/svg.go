@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// highlighted devices are labelled and coloured in the graph rendering.
+var highlighted = map[string]string{
+	"you": "#ffff66",
+	"svr": "#ffff66",
+	"dac": "#ff6666",
+	"fft": "#ff6666",
+	"out": "#00cc00",
+}
+
+// SVG renders the device graph in layers, left to right, where each
+// device sits one layer after the deepest device feeding into it. Device
+// names come from the input, so they are escaped before being written.
+func (r *Reactor) SVG() string {
+	layers := r.layers()
+
+	const dx, dy = 40, 6
+	pos := make(map[string][2]int)
+	tallest := 0
+	for x, layer := range layers {
+		for y, name := range layer {
+			pos[name] = [2]int{x*dx + dx/2, y*dy + dy}
+		}
+		tallest = max(tallest, len(layer))
+	}
+	width, height := len(layers)*dx, (tallest+1)*dy
+
+	var edges strings.Builder
+	for _, from := range slices.Sorted(maps.Keys(r.graph)) {
+		p := pos[from]
+		for _, to := range r.graph[from] {
+			q := pos[to]
+			fmt.Fprintf(&edges, "M%d %dL%d %d", p[0], p[1], q[0], q[1])
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d">`,
+		width, height, width*2, height*2)
+	fmt.Fprintf(&sb, `<rect width="%d" height="%d" fill="#0f0f23"/>`, width, height)
+	fmt.Fprintf(&sb, `<path fill="none" stroke="#cccccc" stroke-width="0.2" stroke-opacity="0.3" d="%s"/>`,
+		edges.String())
+
+	for _, layer := range layers {
+		for _, name := range layer {
+			p := pos[name]
+			color, ok := highlighted[name]
+			if !ok {
+				fmt.Fprintf(&sb, `<circle cx="%d" cy="%d" r="1.2" fill="#5a5aff"><title>%s</title></circle>`,
+					p[0], p[1], html.EscapeString(name))
+				continue
+			}
+			fmt.Fprintf(&sb, `<circle cx="%d" cy="%d" r="2.5" fill="%s"/>`, p[0], p[1], color)
+			fmt.Fprintf(&sb, `<text x="%d" y="%d" font-size="6" fill="%s">%s</text>`, p[0]+3, p[1]-3, color, html.EscapeString(name))
+		}
+	}
+	sb.WriteString(`</svg>`)
+
+	return sb.String()
+}
+
+// layers groups devices by longest distance from a device with no inputs,
+// using Kahn's algorithm. Devices on a cycle end up in a final layer.
+func (r *Reactor) layers() [][]string {
+	indegree := make(map[string]int)
+	for from, targets := range r.graph {
+		if _, ok := indegree[from]; !ok {
+			indegree[from] = 0
+		}
+		for _, to := range targets {
+			indegree[to]++
+		}
+	}
+
+	depth := make(map[string]int)
+	var queue []string
+	for _, name := range slices.Sorted(maps.Keys(indegree)) {
+		if indegree[name] == 0 {
+			queue = append(queue, name)
+		}
+	}
+
+	placed := 0
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		placed++
+
+		for _, next := range r.graph[cur] {
+			depth[next] = max(depth[next], depth[cur]+1)
+			indegree[next]--
+			if indegree[next] == 0 {
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	deepest := 0
+	for _, d := range depth {
+		deepest = max(deepest, d)
+	}
+	if placed < len(indegree) {
+		deepest++
+	}
+
+	layers := make([][]string, deepest+1)
+	for _, name := range slices.Sorted(maps.Keys(indegree)) {
+		d := depth[name]
+		if indegree[name] > 0 {
+			d = deepest
+		}
+		layers[d] = append(layers[d], name)
+	}
+	return layers
+}
@@ -0,0 +1,48 @@
+package report
+
+import (
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// Budget caps what a day's solver may allocate for an input, for its tests.
+type Budget struct {
+	Allocs int    // heap allocations
+	Bytes  uint64 // total bytes allocated, including garbage
+	Peak   uint64 // most heap in use at once, zero to leave unchecked
+}
+
+// Check parses and solves input with solve, and fails t if any measurement
+// is over budget. The heap only shrinks when the collector runs, so Peak is
+// skipped for runs it didn't interrupt, where the peak is just Bytes.
+func (b Budget) Check(t testing.TB, input string, solve func(io.Reader)) {
+	t.Helper()
+
+	allocs := testing.AllocsPerRun(5, func() {
+		solve(strings.NewReader(input))
+	})
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	run, err := Measure(0, strings.NewReader(input), solve)
+	if err != nil {
+		t.Fatal(err)
+	}
+	runtime.ReadMemStats(&after)
+
+	// Measure collects once before solving
+	collected := after.NumGC-before.NumGC > 1
+
+	t.Logf("allocs: %.0f/%d, bytes: %d/%d, peak: %d/%d", allocs, b.Allocs, run.Bytes, b.Bytes, run.PeakBytes, b.Peak)
+	if allocs > float64(b.Allocs) {
+		t.Errorf("%.0f allocations, over the budget of %d", allocs, b.Allocs)
+	}
+	if run.Bytes > b.Bytes {
+		t.Errorf("%d bytes allocated, over the budget of %d", run.Bytes, b.Bytes)
+	}
+	if b.Peak > 0 && collected && run.PeakBytes > b.Peak {
+		t.Errorf("%d bytes of heap at peak, over the budget of %d", run.PeakBytes, b.Peak)
+	}
+}
@@ -0,0 +1,112 @@
+// Package report measures a day's solver and describes the run as JSON,
+// so the aoc command can collect every day into a single HTML report.
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"runtime"
+	"runtime/metrics"
+	"time"
+)
+
+// heapObjects is the runtime metric for bytes of heap objects, both live and
+// not yet collected, the same as MemStats.HeapAlloc but read without
+// stopping the world.
+const heapObjects = "/memory/classes/heap/objects:bytes"
+
+// sampleEvery is how often the heap is sampled while solving.
+const sampleEvery = 100 * time.Microsecond
+
+// Run is the outcome of solving one day's input.
+type Run struct {
+	Day       int           `json:"day"`
+	Answers   []string      `json:"answers"`
+	ReadTime  time.Duration `json:"read_ns"`  // loading the input into memory
+	ParseTime time.Duration `json:"parse_ns"` // parsing the input, zero when it is parsed while solving
+	SolveTime time.Duration `json:"solve_ns"` // solving the parsed input, including parsing when ParseTime is zero
+	Allocs    uint64        `json:"allocs"`   // heap allocations made while parsing and solving
+	Bytes     uint64        `json:"bytes"`    // heap bytes allocated while parsing and solving
+	PeakBytes uint64        `json:"peak"`     // most heap in use while parsing and solving, above the heap before
+	SVG       string        `json:"svg,omitempty"`
+}
+
+// Measure reads r into memory and then runs solve over it, for solvers that
+// solve each line as they parse it. Parsing is timed as part of solving.
+func Measure(day int, r io.Reader, solve func(io.Reader)) (Run, error) {
+	return MeasureSteps(day, r, solve, nil)
+}
+
+// MeasureSteps reads r into memory, parses it with parse and then runs
+// solve, recording the time of each phase and the allocations of both.
+// When solve is nil, parse also solves and its time is recorded as solving.
+func MeasureSteps(day int, r io.Reader, parse func(io.Reader), solve func()) (Run, error) {
+	run := Run{Day: day}
+
+	start := time.Now()
+	input, err := io.ReadAll(r)
+	if err != nil {
+		return run, err
+	}
+	run.ReadTime = time.Since(start)
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	sampled := sampleHeap()
+	runtime.ReadMemStats(&before)
+
+	start = time.Now()
+	parse(bytes.NewReader(input))
+	if solve == nil {
+		run.SolveTime = time.Since(start)
+	} else {
+		run.ParseTime = time.Since(start)
+		start = time.Now()
+		solve()
+		run.SolveTime = time.Since(start)
+	}
+
+	most := sampled()
+	runtime.ReadMemStats(&after)
+	run.Allocs = after.Mallocs - before.Mallocs
+	run.Bytes = after.TotalAlloc - before.TotalAlloc
+	run.PeakBytes = max(most, after.HeapAlloc, before.HeapAlloc) - before.HeapAlloc
+
+	return run, nil
+}
+
+// sampleHeap starts sampling the heap in the background and returns a
+// function that stops it and returns the most heap in use it saw. Samples
+// can lag recent allocations, so the heap at the end is read exactly with
+// runtime.ReadMemStats. Everything it needs is allocated before it returns,
+// so it isn't counted in Allocs or Bytes.
+func sampleHeap() func() uint64 {
+	sample := []metrics.Sample{{Name: heapObjects}}
+	stop, result := make(chan struct{}), make(chan uint64)
+	ticker := time.NewTicker(sampleEvery)
+	go func() {
+		var most uint64
+		for {
+			select {
+			case <-stop:
+				result <- most
+				return
+			case <-ticker.C:
+				metrics.Read(sample)
+				most = max(most, sample[0].Value.Uint64())
+			}
+		}
+	}()
+
+	return func() uint64 {
+		ticker.Stop()
+		close(stop)
+		return <-result
+	}
+}
+
+// Write encodes the run as a single line of JSON.
+func (r Run) Write(w io.Writer) error {
+	return json.NewEncoder(w).Encode(r)
+}
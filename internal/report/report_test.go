@@ -0,0 +1,68 @@
+package report_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/lcox74/aoc25/internal/report"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeasure(t *testing.T) {
+	var got string
+	run, err := report.Measure(3, strings.NewReader("hello"), func(r io.Reader) {
+		b, _ := io.ReadAll(r)
+		got = string(b)
+	})
+	require.NoError(t, err)
+	require.Equal(t, "hello", got)
+	require.Equal(t, 3, run.Day)
+	require.Positive(t, run.Allocs)
+
+	run.Answers = []string{"1", "2"}
+	var buf bytes.Buffer
+	require.NoError(t, run.Write(&buf))
+
+	var decoded report.Run
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Equal(t, run, decoded)
+}
+
+func TestMeasureSteps(t *testing.T) {
+	var parsed, solved string
+	run, err := report.MeasureSteps(4, strings.NewReader("hello"), func(r io.Reader) {
+		b, _ := io.ReadAll(r)
+		parsed = string(b)
+	}, func() {
+		solved = strings.ToUpper(parsed)
+	})
+	require.NoError(t, err)
+	require.Equal(t, "HELLO", solved)
+	require.Positive(t, run.ParseTime)
+
+	run, err = report.Measure(4, strings.NewReader("hello"), func(io.Reader) {})
+	require.NoError(t, err)
+	require.Zero(t, run.ParseTime)
+}
+
+func TestPeakBytes(t *testing.T) {
+	var keep []byte
+	run, err := report.Measure(8, strings.NewReader(""), func(io.Reader) {
+		keep = make([]byte, 1<<20)
+	})
+	require.NoError(t, err)
+	require.Len(t, keep, 1<<20)
+	require.GreaterOrEqual(t, run.PeakBytes, uint64(1<<20))
+	require.LessOrEqual(t, run.PeakBytes, run.Bytes+64<<10)
+}
+
+func TestBudget(t *testing.T) {
+	budget := report.Budget{Allocs: 20, Bytes: 64 << 10, Peak: 64 << 10}
+	budget.Check(t, "hello", func(r io.Reader) {
+		_, err := io.ReadAll(r)
+		require.NoError(t, err)
+	})
+}